@@ -0,0 +1,42 @@
+package response
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Changelog is the release notes of the versions of a service, rendered by GET /api/v1/services/{name}/changelog
+// as JSON or with its Markdown method.
+type Changelog struct {
+	Service string `json:"serviceName"`
+	// Entries are ordered newest first.
+	Entries []ChangelogEntry `json:"entries"`
+} //@name Changelog
+
+type ChangelogEntry struct {
+	Version   string    `json:"version" example:"1.4.0"`
+	Author    string    `json:"author,omitempty" example:"jane.doe"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Changelog is empty for the versions published without release notes.
+	Changelog string `json:"changelog"`
+} //@name ChangelogEntry
+
+// Markdown renders the changelog as a Markdown document with a section per version.
+func (c Changelog) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s changelog\n", c.Service)
+	for _, e := range c.Entries {
+		fmt.Fprintf(&b, "\n## %s\n\n_Published %s", e.Version, e.CreatedAt.UTC().Format(time.RFC3339))
+		if e.Author != "" {
+			fmt.Fprintf(&b, " by %s", e.Author)
+		}
+		b.WriteString("_\n\n")
+		if notes := strings.TrimSpace(e.Changelog); notes != "" {
+			b.WriteString(notes + "\n")
+		} else {
+			b.WriteString("No release notes.\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package response
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestChangelogMarkdown(t *testing.T) {
+	published := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	changelog := Changelog{Service: "payments", Entries: []ChangelogEntry{
+		{Version: "2.0.0", Author: "jane.doe", CreatedAt: published, Changelog: "- Adds refunds.\n- Drops v1 webhooks.\n"},
+		{Version: "1.0.0", CreatedAt: published.Add(-24 * time.Hour)},
+	}}
+	assert.Equal(t, `# payments changelog
+
+## 2.0.0
+
+_Published 2026-10-18T09:30:00Z by jane.doe_
+
+- Adds refunds.
+- Drops v1 webhooks.
+
+## 1.0.0
+
+_Published 2026-10-17T09:30:00Z_
+
+No release notes.
+`, changelog.Markdown())
+}
@@ -0,0 +1,137 @@
+package response
+
+import (
+	"fmt"
+	"github.com/suyog1pathak/services/pkg/model"
+	"strings"
+)
+
+// Graph is a part of the dependency graph of the catalog, rendered by GET /api/v1/graph with its DOT,
+// Mermaid and JGF methods.
+type Graph struct {
+	// Nodes are ordered by name.
+	Nodes []GraphNode
+	Edges []model.DependencyEdge
+}
+
+// GraphNode is a service of the graph described by its current version, a service which is depended on
+// but no longer exists has no version.
+type GraphNode struct {
+	Name    string
+	Version string
+	Owner   string
+}
+
+// JSONGraph is a graph in the JSON Graph Format, https://jsongraphformat.info.
+type JSONGraph struct {
+	Graph JSONGraphBody `json:"graph"`
+} //@name JSONGraph
+
+type JSONGraphBody struct {
+	Label    string `json:"label"`
+	Directed bool   `json:"directed"`
+	// Nodes are keyed by service name.
+	Nodes map[string]JSONGraphNode `json:"nodes"`
+	Edges []JSONGraphEdge          `json:"edges"`
+} //@name JSONGraphBody
+
+type JSONGraphNode struct {
+	Label    string            `json:"label"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+} //@name JSONGraphNode
+
+type JSONGraphEdge struct {
+	Source   string            `json:"source"`
+	Target   string            `json:"target"`
+	Relation string            `json:"relation"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+} //@name JSONGraphEdge
+
+// DOT renders the graph in the Graphviz DOT language, edges are labelled with their version constraint.
+func (g Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph services {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "\t%s [label=%s];\n", dotID(n.Name), dotID(n.label("\n")))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s", dotID(e.Service), dotID(e.Dependency))
+		if e.Constraint != "" {
+			fmt.Fprintf(&b, " [label=%s]", dotID(e.Constraint))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart, nodes get generated ids as service names are not valid ids.
+func (g Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	ids := map[string]string{}
+	for i, n := range g.Nodes {
+		ids[n.Name] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "\t%s[%s]\n", ids[n.Name], mermaidText(n.label("<br>")))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -->", ids[e.Service])
+		if e.Constraint != "" {
+			fmt.Fprintf(&b, "|%s|", mermaidText(e.Constraint))
+		}
+		fmt.Fprintf(&b, " %s\n", ids[e.Dependency])
+	}
+	return b.String()
+}
+
+// JGF returns the graph in the JSON Graph Format.
+func (g Graph) JGF() JSONGraph {
+	body := JSONGraphBody{
+		Label:    "services",
+		Directed: true,
+		Nodes:    map[string]JSONGraphNode{},
+		Edges:    []JSONGraphEdge{},
+	}
+	for _, n := range g.Nodes {
+		node := JSONGraphNode{Label: n.Name, Metadata: map[string]string{}}
+		if n.Version != "" {
+			node.Metadata["version"] = n.Version
+		}
+		if n.Owner != "" {
+			node.Metadata["owner"] = n.Owner
+		}
+		body.Nodes[n.Name] = node
+	}
+	for _, e := range g.Edges {
+		edge := JSONGraphEdge{
+			Source:   e.Service,
+			Target:   e.Dependency,
+			Relation: "depends on",
+			Metadata: map[string]string{"version": e.Version},
+		}
+		if e.Constraint != "" {
+			edge.Metadata["constraint"] = e.Constraint
+		}
+		body.Edges = append(body.Edges, edge)
+	}
+	return JSONGraph{Graph: body}
+}
+
+// label is the name of the node with its version on a new line.
+func (n GraphNode) label(newline string) string {
+	if n.Version == "" {
+		return n.Name
+	}
+	return n.Name + newline + n.Version
+}
+
+// dotID quotes s as a DOT id, a newline is kept as the \n escape DOT centers labels with.
+func dotID(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// mermaidText quotes s as Mermaid text, quotes are written as the #quot; entity.
+func mermaidText(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
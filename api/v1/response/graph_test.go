@@ -0,0 +1,55 @@
+package response
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/suyog1pathak/services/pkg/model"
+	"testing"
+)
+
+func testGraph() Graph {
+	return Graph{
+		Nodes: []GraphNode{
+			{Name: "ledger", Version: "1.0.0"},
+			{Name: "payments", Version: "2.1.0", Owner: "payments-platform"},
+			{Name: `legacy "core"`},
+		},
+		Edges: []model.DependencyEdge{
+			{Service: "payments", Version: "2.1.0", Dependency: "ledger", Constraint: "^1.0"},
+			{Service: "payments", Version: "2.1.0", Dependency: `legacy "core"`},
+		},
+	}
+}
+
+func TestGraphShouldRenderDOT(t *testing.T) {
+	assert.Equal(t, `digraph services {
+	rankdir=LR;
+	node [shape=box];
+	"ledger" [label="ledger\n1.0.0"];
+	"payments" [label="payments\n2.1.0"];
+	"legacy \"core\"" [label="legacy \"core\""];
+	"payments" -> "ledger" [label="^1.0"];
+	"payments" -> "legacy \"core\"";
+}
+`, testGraph().DOT())
+}
+
+func TestGraphShouldRenderMermaid(t *testing.T) {
+	assert.Equal(t, `flowchart LR
+	n0["ledger<br>1.0.0"]
+	n1["payments<br>2.1.0"]
+	n2["legacy #quot;core#quot;"]
+	n1 -->|"^1.0"| n0
+	n1 --> n2
+`, testGraph().Mermaid())
+}
+
+func TestGraphShouldRenderJGF(t *testing.T) {
+	jgf := testGraph().JGF()
+	assert.True(t, jgf.Graph.Directed)
+	assert.Equal(t, JSONGraphNode{Label: "payments", Metadata: map[string]string{"version": "2.1.0", "owner": "payments-platform"}}, jgf.Graph.Nodes["payments"])
+	assert.Equal(t, JSONGraphNode{Label: `legacy "core"`, Metadata: map[string]string{}}, jgf.Graph.Nodes[`legacy "core"`])
+	assert.Equal(t, []JSONGraphEdge{
+		{Source: "payments", Target: "ledger", Relation: "depends on", Metadata: map[string]string{"version": "2.1.0", "constraint": "^1.0"}},
+		{Source: "payments", Target: `legacy "core"`, Relation: "depends on", Metadata: map[string]string{"version": "2.1.0"}},
+	}, jgf.Graph.Edges)
+}
@@ -1,11 +1,15 @@
 package response
 
-import "github.com/suyog1pathak/services/pkg/model"
+import (
+	"github.com/suyog1pathak/services/pkg/jsonpatch"
+	"github.com/suyog1pathak/services/pkg/model"
+	"time"
+)
 
 type Service struct {
 	*model.Service
-	CurrentVersion int `json:"currentVersion"`
-	TotalVersions  int `json:"totalVersion"`
+	CurrentVersion string `json:"currentVersion" example:"1.4.0"`
+	TotalVersions  int    `json:"totalVersion"`
 } //@name ServiceResponse
 
 type ServicePagination struct {
@@ -18,4 +22,91 @@ type Meta struct {
 	TotalResults int `json:"totalResults"`
 	TotalPages   int `json:"totalPages"`
 	PageSize     int `json:"pageSize"`
+	// Next and Prev are the cursors of the pages after and before this one, the page is 0 on a page fetched by cursor.
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
 } //@name Meta
+
+type PurgeReport struct {
+	DryRun bool      `json:"dryRun"`
+	Cutoff time.Time `json:"cutoff"`
+	// Purged is the number of service versions hard deleted, or which would be in a dry run.
+	Purged   int64           `json:"purged"`
+	Services []PurgedService `json:"services,omitempty"`
+} //@name PurgeReport
+
+type PurgedService struct {
+	Name      string    `json:"serviceName"`
+	Version   string    `json:"version" example:"1.4.0"`
+	DeletedAt time.Time `json:"deletedAt"`
+} //@name PurgedService
+
+// DependencyGraph is the part of the dependency graph reached from a service, its dependencies or its dependents.
+type DependencyGraph struct {
+	Service string `json:"serviceName"`
+	Version string `json:"version" example:"1.4.0"`
+	Depth   int    `json:"depth"`
+	// Edges are ordered by depth, each service reached is followed once.
+	Edges []DependencyEdge `json:"edges"`
+} //@name DependencyGraph
+
+type DependencyEdge struct {
+	model.DependencyEdge
+	// Depth is the distance of the edge from the service, 1 for its direct dependencies or dependents.
+	Depth int `json:"depth"`
+} //@name DependencyEdge
+
+// Facets counts the services matching the listing filters per label, active state and owner. A service is
+// counted once for every value carried by one of its matching live versions.
+type Facets struct {
+	// Total is the number of services matching the filters.
+	Total int64 `json:"total"`
+	// Labels are keyed by label key, a plain tag is counted under an empty value.
+	Labels   map[string][]FacetValue `json:"labels"`
+	IsActive []FacetValue            `json:"isActive"`
+	// Owner counts the services without an owner under an empty value.
+	Owner []FacetValue `json:"owner"`
+} //@name Facets
+
+type FacetValue struct {
+	Value string `json:"value" example:"payments-platform"`
+	Count int64  `json:"count"`
+} //@name FacetValue
+
+// VersionDiff is what changed between two versions of a service, as a JSON Patch and as unified text.
+type VersionDiff struct {
+	Service string `json:"serviceName"`
+	From    string `json:"from" example:"1.0.0"`
+	To      string `json:"to" example:"2.0.0"`
+	// Patch turns the fields of the from version into those of the to version.
+	Patch []jsonpatch.Operation `json:"patch"`
+	// Unified renders the fields of both versions as a unified diff, empty when they do not differ.
+	Unified string `json:"unified"`
+} //@name VersionDiff
+
+// DeprecationReport lists the deprecated versions sunsetting within a number of days, along with the ones
+// past their sunset date which are not retired yet.
+type DeprecationReport struct {
+	Days  int       `json:"days"`
+	Until time.Time `json:"until"`
+	// Deprecations are ordered by sunset date.
+	Deprecations []Deprecation `json:"deprecations"`
+} //@name DeprecationReport
+
+type Deprecation struct {
+	Service      string     `json:"serviceName"`
+	Version      string     `json:"version" example:"1.4.0"`
+	Owner        string     `json:"owner,omitempty" example:"payments-platform"`
+	DeprecatedAt *time.Time `json:"deprecatedAt,omitempty"`
+	SunsetDate   time.Time  `json:"sunsetDate"`
+	Successor    string     `json:"successor,omitempty" example:"payments-v2"`
+	// DaysLeft is the number of whole days until the sunset date, negative once it has passed.
+	DaysLeft int `json:"daysLeft"`
+} //@name Deprecation
+
+// AuditLog is a page of the audit log, in the order the events were appended.
+type AuditLog struct {
+	Events []model.AuditEvent `json:"events"`
+	// NextSinceID is the since_id of the next page, it is left out on the last page.
+	NextSinceID uint `json:"nextSinceId,omitempty"`
+} //@name AuditLog
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/suyog1pathak/services/internal/purge"
+	"github.com/suyog1pathak/services/pkg/config"
+	"github.com/suyog1pathak/services/pkg/datastore"
+	"github.com/suyog1pathak/services/pkg/model"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only report the service versions which would be purged")
+	flag.Parse()
+
+	db, err := datastore.GetDBConnection()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	c := config.GetConfig()
+	report, err := purge.NewPurger(model.NewGormServiceRepository(db), c.App.PurgeAfter, c.App.PurgeBatchSize).Run(*dryRun)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(out))
+}
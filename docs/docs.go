@@ -17,9 +17,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/services": {
-            "get": {
-                "description": "list and filter services with pagination",
+        "/api/v1/admin/purge": {
+            "post": {
+                "description": "hard delete the service versions soft deleted for longer than the configured retention window",
                 "consumes": [
                     "application/json"
                 ],
@@ -27,102 +27,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "admin"
                 ],
-                "summary": "list and filter services with pagination",
+                "summary": "purge deleted services",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "Service1",
-                        "description": "query to filter, service name only",
-                        "name": "query",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "desc",
-                            "asc"
-                        ],
-                        "type": "string",
-                        "description": "direction of sorting",
-                        "name": "dir",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 1000,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "page no",
-                        "name": "page",
+                        "type": "boolean",
+                        "description": "only report the service versions which would be purged",
+                        "name": "dry_run",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "created_at",
-                            "updated_at",
-                            "version"
-                        ],
                         "type": "string",
-                        "description": "sort by column name",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 10,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "page size",
-                        "name": "pagesize",
-                        "in": "query"
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ServicePagination"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/GenericErrorResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "List services",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "services"
-                ],
-                "summary": "List services",
-                "parameters": [
-                    {
-                        "description": "Add Service",
-                        "name": "service",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/ServiceModelDb"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/ServiceResponse"
+                            "$ref": "#/definitions/PurgeReport"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/GenericErrorResponse"
                         }
@@ -136,36 +66,74 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/services/{name}": {
+        "/api/v1/audit": {
             "get": {
-                "description": "Get services by name",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "changes made to services and teams with who made them and their before and after JSON, in the order they were made.\nthe actor is the X-User header of the request as it was sent, the api does not authenticate it: it can only\nbe trusted when a proxy authenticating the callers sets the header and strips the one sent by clients.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "services"
                 ],
-                "summary": "Get services by name",
+                "summary": "Audit log",
                 "parameters": [
                     {
                         "type": "string",
+                        "example": "payments",
                         "description": "service name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "name": "service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "payments-platform",
+                        "description": "team name",
+                        "name": "team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "jane.doe",
+                        "description": "who made the changes, the self-asserted X-User header of their requests",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-01-01T00:00:00Z",
+                        "description": "RFC3339 time the changes are made at or after",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-02-01T00:00:00Z",
+                        "description": "RFC3339 time the changes are made before",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "id of the last event of the previous page",
+                        "name": "since_id",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "events per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/ServiceModelDb"
-                            }
+                            "$ref": "#/definitions/AuditLog"
                         }
                     },
                     "400": {
@@ -174,12 +142,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/GenericErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/GenericErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -187,42 +149,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "update service // create new version",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/deprecations": {
+            "get": {
+                "description": "deprecated service versions sunsetting within the given number of days, along with the ones past their sunset date which are not retired yet, ordered by sunset date",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "services"
                 ],
-                "summary": "update service // create new version",
+                "summary": "Deprecation report",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "service name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "update Service",
-                        "name": "service",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/ServiceModelDb"
-                        }
+                        "maximum": 3650,
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 30,
+                        "description": "days ahead",
+                        "name": "days",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ServiceResponse"
+                            "$ref": "#/definitions/DeprecationReport"
                         }
                     },
                     "400": {
@@ -240,33 +194,69 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/services/{name}/": {
-            "delete": {
-                "description": "delete service",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/facets": {
+            "get": {
+                "description": "count the services matching the listing filters per label value, active state and owner, a service is counted once for every value one of its matching live versions carries",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "services"
                 ],
-                "summary": "delete service",
+                "summary": "facet counts",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "service name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "example": "payments",
+                        "description": "case-insensitive text to search for in the service name, description and tags",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "contains",
+                            "prefix"
+                        ],
+                        "type": "string",
+                        "default": "contains",
+                        "description": "find the query anywhere in a field or at its start, for tags at the start of any tag",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name,tags",
+                        "description": "comma separated fields to search, all of them by default",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "payments-platform",
+                        "description": "comma separated names of the teams owning the services",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "tier=1,team in (payments,core",
+                        "description": "kubernetes style label selector, the counted services have a version with matching labels",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "production,deprecated",
+                        "description": "comma separated lifecycle states, the counted services have a version in one of them",
+                        "name": "lifecycle",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/GenericResponse"
+                            "$ref": "#/definitions/Facets"
                         }
                     },
                     "400": {
@@ -275,12 +265,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/GenericErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/GenericErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -290,40 +274,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/services/{name}/{version}": {
+        "/api/v1/graph": {
             "get": {
-                "description": "Get service by version and name",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "render the dependency graph of the current versions of the catalog, or of the dependencies or dependents of a service, as JSON Graph Format, Graphviz DOT or a Mermaid flowchart picked by the format param or else the Accept header",
                 "produces": [
-                    "application/json"
+                    "application/vnd.jgf+json",
+                    "application/json",
+                    "text/vnd.graphviz",
+                    "text/vnd.mermaid"
                 ],
                 "tags": [
                     "services"
                 ],
-                "summary": "Get service by version and name",
+                "summary": "dependency graph",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "service name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "description": "service the graph is rooted at, the whole catalog by default",
+                        "name": "root",
+                        "in": "query"
                     },
                     {
+                        "enum": [
+                            "dependencies",
+                            "dependents"
+                        ],
                         "type": "string",
-                        "description": "version name",
-                        "name": "version",
-                        "in": "path",
-                        "required": true
+                        "default": "dependencies",
+                        "description": "edges followed from the root",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 32,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "levels followed from the root",
+                        "name": "depth",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "jgf",
+                            "dot",
+                            "mermaid"
+                        ],
+                        "type": "string",
+                        "description": "rendering, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ServiceModelDb"
+                            "$ref": "#/definitions/JSONGraph"
                         }
                     },
                     "400": {
@@ -338,6 +345,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/GenericErrorResponse"
                         }
                     },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -345,9 +358,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "update service version",
+            }
+        },
+        "/api/v1/services": {
+            "get": {
+                "description": "list and filter services with pagination",
                 "consumes": [
                     "application/json"
                 ],
@@ -357,37 +372,1650 @@ const docTemplate = `{
                 "tags": [
                     "services"
                 ],
-                "summary": "update service version",
+                "summary": "list and filter services with pagination",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "service name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "example": "payments",
+                        "description": "case-insensitive text to search for in the service name, description and tags",
+                        "name": "query",
+                        "in": "query"
                     },
                     {
+                        "enum": [
+                            "contains",
+                            "prefix"
+                        ],
                         "type": "string",
-                        "description": "version",
-                        "name": "version",
-                        "in": "path",
-                        "required": true
+                        "default": "contains",
+                        "description": "find the query anywhere in a field or at its start, for tags at the start of any tag",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name,tags",
+                        "description": "comma separated fields to search, all of them by default",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "payments-platform",
+                        "description": "comma separated names of the teams owning the services",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "tier=1,team in (payments,core",
+                        "description": "kubernetes style label selector, the listed services have a version with matching labels",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "production,deprecated",
+                        "description": "comma separated lifecycle states, the listed services have a version in one of them",
+                        "name": "lifecycle",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "desc",
+                            "asc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "direction of the sort keys without a - or + prefix",
+                        "name": "dir",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "page no",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "example": "name,-updated_at",
+                        "description": "comma separated sort keys out of name, created_at, updated_at, version, version_count, prefixed with - for descending or + for ascending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 10,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "page size",
+                        "name": "pagesize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next or prev cursor of a previous page with the same sort, page is then ignored",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted service versions instead, other filters are ignored",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-01-31T12:00:00Z",
+                        "description": "RFC3339 time to list the catalog as it was at, including services deleted since. The past catalog is sorted by name, ascending unless dir is desc, and can not be searched nor read by cursor",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ServicePagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "List services",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List services",
+                "parameters": [
+                    {
+                        "description": "Add Service",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ServiceModelDb"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "author of the version, recorded as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "unique key of the request for its path and X-User, a retry with the same key gets the stored response instead of repeating the request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ServiceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{name}": {
+            "get": {
+                "description": "Get services by name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get services by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-01-31T12:00:00Z",
+                        "description": "RFC3339 time to describe the service as it was at",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ServiceModelDb"
+                            }
+                        },
+                        "headers": {
+                            "Deprecation": {
+                                "type": "string",
+                                "description": "@\u003cunix time\u003e the current version was deprecated at, when it is deprecated or retired"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "successor service of the current version, rel=successor-version"
+                            },
+                            "Sunset": {
+                                "type": "string",
+                                "description": "HTTP date the current version is sunset at"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "update service // create new version\nthe new version is either set explicitly with ` + "`" + `version` + "`" + ` or derived from the latest version with ` + "`" + `bump` + "`" + ` (major, minor or patch), major is bumped by default.\nthe release notes of the version are given in ` + "`" + `changelog` + "`" + `, the X-User header is recorded as its author.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "update service // create new version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update Service",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ServiceModelDb"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "author of the version, recorded as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "unique key of the request for its path and X-User, a retry with the same key gets the stored response instead of repeating the request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ServiceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{name}/": {
+            "delete": {
+                "description": "delete service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "delete service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{name}/changelog": {
+            "get": {
+                "description": "release notes of all live versions of the service with their author and publication time, newest first, as JSON or Markdown picked by the format param or else the Accept header",
+                "produces": [
+                    "application/json",
+                    "text/markdown"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Service changelog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "markdown"
+                        ],
+                        "type": "string",
+                        "description": "rendering, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Changelog"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{name}/dependencies": {
+            "get": {
+                "description": "List the services a version of the service depends on, transitively down to depth levels through the current versions of the dependencies",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List service dependencies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "semantic version, the current version by default",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 32,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "levels of dependencies to follow",
+                        "name": "depth",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DependencyGraph"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{name}/dependents": {
+            "get": {
+                "description": "List the services whose current version depends on the service, transitively up to depth levels, i.e. what breaks if the service goes down",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List service dependents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 32,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "levels of dependents to follow",
+                        "name": "depth",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DependencyGraph"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{name}/diff": {
+            "get": {
+                "description": "field level diff between two versions of the service, of the description, state, tags, labels and any other field, as a JSON Patch (RFC 6902), unified text or both, picked by the format param or else the Accept header",
+                "produces": [
+                    "application/json",
+                    "application/json-patch+json",
+                    "text/x-diff",
+                    "text/plain"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Diff two service versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "1.0.0",
+                        "description": "semantic version compared from, partial versions like 2 are read as 2.0.0",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2.0.0",
+                        "description": "semantic version compared to",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "patch",
+                            "unified"
+                        ],
+                        "type": "string",
+                        "description": "rendering, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/VersionDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{name}/promote/{version}": {
+            "post": {
+                "description": "make the version the current version of the service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "promote service version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "semantic version, e.g. 1.4.0",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ServiceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{name}/restore": {
+            "post": {
+                "description": "undo the last deletion of the service, or of a single version when version is given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "restore service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "semantic version to restore, e.g. 1.4.0",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ServiceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{name}/rollback": {
+            "post": {
+                "description": "point the service at the highest released version lower than its current version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "rollback service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ServiceResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{name}/versions": {
+            "get": {
+                "description": "List service versions ordered by semver precedence, optionally filtered by a version constraint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List service versions matching a constraint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "^1.2",
+                        "description": "semver constraint, e.g. ^1.2, ~1.2.3, 1.x or \u003e=1.0 \u003c2",
+                        "name": "constraint",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ServiceModelDb"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{name}/{version}": {
+            "get": {
+                "description": "Get service by version and name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get service by version and name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "semantic version, e.g. 1.4.0",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-01-31T12:00:00Z",
+                        "description": "RFC3339 time to describe the version as it was at",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version as it was read, 304 is returned when it did not change since",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ServiceModelDb"
+                        },
+                        "headers": {
+                            "Deprecation": {
+                                "type": "string",
+                                "description": "@\u003cunix time\u003e the version was deprecated at, when it is deprecated or retired"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of the version"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "successor service of the version, rel=successor-version"
+                            },
+                            "Sunset": {
+                                "type": "string",
+                                "description": "HTTP date the version is sunset at"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "the version did not change since it was read",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of the version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete a single service version, the current version is only deleted with force and the service is then pointed at its highest remaining release",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "delete service version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "semantic version, e.g. 1.4.0",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "delete even if it is the current version",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "update service version, a JSON body updates the fields which are set while a JSON Merge Patch (RFC 7396)\nor a JSON Patch (RFC 6902) of the version writes exactly the members it changes, false, empty and null included",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "update service version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "semantic version, e.g. 1.4.0",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update Service, or a patch of it",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ServiceModelDb"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version as it was read, the update fails with 412 when it was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ServiceModelDb"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of the updated version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teams": {
+            "get": {
+                "description": "list all teams with their owners",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "list teams",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Team"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a team along with its owners",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "create team",
+                "parameters": [
+                    {
+                        "description": "team, the name is 1 to 50 lowercase letters, digits or dashes",
+                        "name": "team",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Team"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teams/{team}": {
+            "get": {
+                "description": "get a team with its owners",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "get team",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "team name",
+                        "name": "team",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Team"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete a team and its owners, the team must not own any service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "delete team",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "team name",
+                        "name": "team",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/GenericResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "update the description of a team, owners are added and removed with the owners endpoints",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "update team",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "team name",
+                        "name": "team",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "team, only the description is updated",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Team"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teams/{team}/owners": {
+            "post": {
+                "description": "add an owner to a team",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "add owner",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "team name",
+                        "name": "team",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "owner",
+                        "name": "owner",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Owner"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teams/{team}/owners/{email}": {
+            "delete": {
+                "description": "remove an owner from a team",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "remove owner",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "team name",
+                        "name": "team",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "update Service",
-                        "name": "service",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "owner email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "who makes the change, recorded in the audit log as sent, it is not authenticated",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/ServiceModelDb"
+                            "$ref": "#/definitions/GenericResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/api/v1/teams/{team}/services": {
+            "get": {
+                "description": "list the services owned by a team, with the search, sort and pagination of the service listing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "list team services",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "team name",
+                        "name": "team",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "case-insensitive text to search for in the service name, description and tags",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "kubernetes style label selector, the listed services have a version with matching labels",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "production,deprecated",
+                        "description": "comma separated lifecycle states, the listed services have a version in one of them",
+                        "name": "lifecycle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "comma separated sort keys out of name, created_at, updated_at, version, version_count, prefixed with - for descending or + for ascending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "desc",
+                            "asc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "direction of the sort keys without a - or + prefix",
+                        "name": "dir",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "page no",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "page size",
+                        "name": "pagesize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next or prev cursor of a previous page with the same sort, page is then ignored",
+                        "name": "cursor",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ServiceModelDb"
+                            "$ref": "#/definitions/ServicePagination"
                         }
                     },
                     "400": {
@@ -396,6 +2024,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/GenericErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/GenericErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -434,71 +2068,331 @@ const docTemplate = `{
                 }
             }
         },
-        "/liveness": {
-            "get": {
-                "description": "liveness",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "healthcheck"
-                ],
-                "summary": "liveness",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/HealthcheckResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/HealthcheckResponse"
-                        }
-                    }
+        "/liveness": {
+            "get": {
+                "description": "liveness",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "healthcheck"
+                ],
+                "summary": "liveness",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/HealthcheckResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/HealthcheckResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readiness": {
+            "get": {
+                "description": "ReadinessCheck",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "healthcheck"
+                ],
+                "summary": "ReadinessCheck",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/HealthcheckResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/HealthcheckResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "AuditEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "create_version",
+                        "update_version",
+                        "promote",
+                        "rollback",
+                        "delete",
+                        "delete_version",
+                        "restore",
+                        "purge",
+                        "create_team",
+                        "update_team",
+                        "delete_team",
+                        "add_owner",
+                        "remove_owner"
+                    ]
+                },
+                "actor": {
+                    "description": "Actor is who made the change, the X-User header of the request.",
+                    "type": "string",
+                    "example": "jane.doe"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "requestId": {
+                    "type": "string",
+                    "example": "4b1c8e5e-0c6c-4f43-9d1b-1f0f6a8e2f55"
+                },
+                "serviceName": {
+                    "description": "Service is the name of the changed service, empty for changes to teams.",
+                    "type": "string",
+                    "example": "payments"
+                },
+                "teamName": {
+                    "description": "Team is the name of the changed team, empty for changes to services.",
+                    "type": "string",
+                    "example": "payments-platform"
+                },
+                "version": {
+                    "description": "Version is the changed version, empty for changes to the service as a whole.",
+                    "type": "string",
+                    "example": "1.4.0"
+                }
+            }
+        },
+        "AuditLog": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AuditEvent"
+                    }
+                },
+                "nextSinceId": {
+                    "description": "NextSinceID is the since_id of the next page, it is left out on the last page.",
+                    "type": "integer"
+                }
+            }
+        },
+        "Changelog": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Entries are ordered newest first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ChangelogEntry"
+                    }
+                },
+                "serviceName": {
+                    "type": "string"
+                }
+            }
+        },
+        "ChangelogEntry": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "jane.doe"
+                },
+                "changelog": {
+                    "description": "Changelog is empty for the versions published without release notes.",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.0"
+                }
+            }
+        },
+        "Components": {
+            "type": "object",
+            "properties": {
+                "datastore": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "Dependency": {
+            "type": "object",
+            "properties": {
+                "constraint": {
+                    "description": "Constraint is a semver constraint on the versions of the dependency, any version when it is empty.",
+                    "type": "string",
+                    "example": "^1.2"
+                },
+                "serviceName": {
+                    "type": "string",
+                    "example": "ledger"
+                }
+            }
+        },
+        "DependencyEdge": {
+            "type": "object",
+            "properties": {
+                "constraint": {
+                    "type": "string"
+                },
+                "dependency": {
+                    "type": "string"
+                },
+                "depth": {
+                    "description": "Depth is the distance of the edge from the service, 1 for its direct dependencies or dependents.",
+                    "type": "integer"
+                },
+                "serviceName": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.0"
+                }
+            }
+        },
+        "DependencyGraph": {
+            "type": "object",
+            "properties": {
+                "depth": {
+                    "type": "integer"
+                },
+                "edges": {
+                    "description": "Edges are ordered by depth, each service reached is followed once.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DependencyEdge"
+                    }
+                },
+                "serviceName": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.0"
+                }
+            }
+        },
+        "Deprecation": {
+            "type": "object",
+            "properties": {
+                "daysLeft": {
+                    "description": "DaysLeft is the number of whole days until the sunset date, negative once it has passed.",
+                    "type": "integer"
+                },
+                "deprecatedAt": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "payments-platform"
+                },
+                "serviceName": {
+                    "type": "string"
+                },
+                "successor": {
+                    "type": "string",
+                    "example": "payments-v2"
+                },
+                "sunsetDate": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.0"
                 }
             }
         },
-        "/readiness": {
-            "get": {
-                "description": "ReadinessCheck",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "healthcheck"
-                ],
-                "summary": "ReadinessCheck",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/HealthcheckResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/HealthcheckResponse"
-                        }
+        "DeprecationReport": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "deprecations": {
+                    "description": "Deprecations are ordered by sunset date.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Deprecation"
                     }
+                },
+                "until": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "Components": {
+        },
+        "FacetValue": {
             "type": "object",
             "properties": {
-                "datastore": {
-                    "type": "boolean"
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "payments-platform"
+                }
+            }
+        },
+        "Facets": {
+            "type": "object",
+            "properties": {
+                "isActive": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FacetValue"
+                    }
+                },
+                "labels": {
+                    "description": "Labels are keyed by label key, a plain tag is counted under an empty value.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/FacetValue"
+                        }
+                    }
+                },
+                "owner": {
+                    "description": "Owner counts the services without an owner under an empty value.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FacetValue"
+                    }
+                },
+                "total": {
+                    "description": "Total is the number of services matching the filters.",
+                    "type": "integer"
                 }
             }
         },
@@ -538,15 +2432,115 @@ const docTemplate = `{
                 }
             }
         },
+        "JSONGraph": {
+            "type": "object",
+            "properties": {
+                "graph": {
+                    "$ref": "#/definitions/JSONGraphBody"
+                }
+            }
+        },
+        "JSONGraphBody": {
+            "type": "object",
+            "properties": {
+                "directed": {
+                    "type": "boolean"
+                },
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/JSONGraphEdge"
+                    }
+                },
+                "label": {
+                    "type": "string"
+                },
+                "nodes": {
+                    "description": "Nodes are keyed by service name.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/JSONGraphNode"
+                    }
+                }
+            }
+        },
+        "JSONGraphEdge": {
+            "type": "object",
+            "properties": {
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "relation": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "JSONGraphNode": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "JSONPatchOperation": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "From is the location a move or copy operation takes its value from.",
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "add",
+                        "remove",
+                        "replace",
+                        "move",
+                        "copy",
+                        "test"
+                    ]
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/labels/tier"
+                },
+                "value": {
+                    "type": "object"
+                }
+            }
+        },
         "Meta": {
             "type": "object",
             "properties": {
+                "next": {
+                    "description": "Next and Prev are the cursors of the pages after and before this one, the page is 0 on a page fetched by cursor.",
+                    "type": "string"
+                },
                 "page": {
                     "type": "integer"
                 },
                 "pageSize": {
                     "type": "integer"
                 },
+                "prev": {
+                    "type": "string"
+                },
                 "totalPages": {
                     "type": "integer"
                 },
@@ -555,20 +2549,158 @@ const docTemplate = `{
                 }
             }
         },
+        "Owner": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "jane@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Jane Doe"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "PurgeReport": {
+            "type": "object",
+            "properties": {
+                "cutoff": {
+                    "type": "string"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "purged": {
+                    "description": "Purged is the number of service versions hard deleted, or which would be in a dry run.",
+                    "type": "integer"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/PurgedService"
+                    }
+                }
+            }
+        },
+        "PurgedService": {
+            "type": "object",
+            "properties": {
+                "deletedAt": {
+                    "type": "string"
+                },
+                "serviceName": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.0"
+                }
+            }
+        },
         "ServiceModelDb": {
             "type": "object",
             "properties": {
+                "author": {
+                    "description": "Author is who published the version, the X-User header of the request creating it.",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "jane.doe"
+                },
+                "bump": {
+                    "description": "Bump is the part of the latest version incremented when a new version is created without an explicit version.",
+                    "type": "string",
+                    "enum": [
+                        "major",
+                        "minor",
+                        "patch"
+                    ]
+                },
+                "changelog": {
+                    "description": "Changelog is the release notes of the version, in Markdown.",
+                    "type": "string",
+                    "example": "Adds refunds."
+                },
+                "dependencies": {
+                    "description": "Dependencies are the services the version depends on, a new version keeps the dependencies of the service unless they are given.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Dependency"
+                    }
+                },
+                "deprecatedAt": {
+                    "description": "DeprecatedAt is when the version was deprecated.",
+                    "type": "string",
+                    "readOnly": true
+                },
                 "describe": {
                     "type": "string"
                 },
                 "isActive": {
-                    "type": "boolean"
+                    "description": "IsActive is derived from the lifecycle for old clients, experimental and production versions are active.",
+                    "type": "boolean",
+                    "readOnly": true
+                },
+                "labels": {
+                    "description": "Labels are the key=value pairs of the version, in the Kubernetes label syntax.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "lifecycle": {
+                    "description": "Lifecycle is the state of the version, it defaults to experimental for prereleases and production otherwise.",
+                    "type": "string",
+                    "enum": [
+                        "experimental",
+                        "production",
+                        "deprecated",
+                        "retired"
+                    ],
+                    "example": "production"
+                },
+                "owner": {
+                    "description": "Owner is the name of the team owning the service, a new version keeps the owner of the service unless one is given.",
+                    "type": "string",
+                    "example": "payments-platform"
+                },
+                "revision": {
+                    "description": "Revision counts the updates of the version, it starts at 1 and is served as its ETag.",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 1
                 },
                 "serviceName": {
                     "type": "string"
                 },
+                "successor": {
+                    "description": "Successor is the service replacing a deprecated version.",
+                    "type": "string",
+                    "example": "payments-v2"
+                },
+                "sunsetDate": {
+                    "description": "SunsetDate is when a deprecated version stops being served. It is cleared along with the successor and\nthe deprecation time when the version goes back into production.",
+                    "type": "string",
+                    "example": "2027-01-31T00:00:00Z"
+                },
                 "tags": {
-                    "type": "string"
+                    "description": "Tags are the labels rendered as comma separated key=value pairs for old clients, they are derived from the labels\nand can not be written, a body with other tags than those of its labels is rejected.",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "tier=1,pci"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.0"
                 }
             }
         },
@@ -589,23 +2721,155 @@ const docTemplate = `{
         "ServiceResponse": {
             "type": "object",
             "properties": {
+                "author": {
+                    "description": "Author is who published the version, the X-User header of the request creating it.",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "jane.doe"
+                },
+                "bump": {
+                    "description": "Bump is the part of the latest version incremented when a new version is created without an explicit version.",
+                    "type": "string",
+                    "enum": [
+                        "major",
+                        "minor",
+                        "patch"
+                    ]
+                },
+                "changelog": {
+                    "description": "Changelog is the release notes of the version, in Markdown.",
+                    "type": "string",
+                    "example": "Adds refunds."
+                },
                 "currentVersion": {
-                    "type": "integer"
+                    "type": "string",
+                    "example": "1.4.0"
+                },
+                "dependencies": {
+                    "description": "Dependencies are the services the version depends on, a new version keeps the dependencies of the service unless they are given.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Dependency"
+                    }
+                },
+                "deprecatedAt": {
+                    "description": "DeprecatedAt is when the version was deprecated.",
+                    "type": "string",
+                    "readOnly": true
                 },
                 "describe": {
                     "type": "string"
                 },
                 "isActive": {
-                    "type": "boolean"
+                    "description": "IsActive is derived from the lifecycle for old clients, experimental and production versions are active.",
+                    "type": "boolean",
+                    "readOnly": true
+                },
+                "labels": {
+                    "description": "Labels are the key=value pairs of the version, in the Kubernetes label syntax.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "lifecycle": {
+                    "description": "Lifecycle is the state of the version, it defaults to experimental for prereleases and production otherwise.",
+                    "type": "string",
+                    "enum": [
+                        "experimental",
+                        "production",
+                        "deprecated",
+                        "retired"
+                    ],
+                    "example": "production"
+                },
+                "owner": {
+                    "description": "Owner is the name of the team owning the service, a new version keeps the owner of the service unless one is given.",
+                    "type": "string",
+                    "example": "payments-platform"
+                },
+                "revision": {
+                    "description": "Revision counts the updates of the version, it starts at 1 and is served as its ETag.",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 1
                 },
                 "serviceName": {
                     "type": "string"
                 },
+                "successor": {
+                    "description": "Successor is the service replacing a deprecated version.",
+                    "type": "string",
+                    "example": "payments-v2"
+                },
+                "sunsetDate": {
+                    "description": "SunsetDate is when a deprecated version stops being served. It is cleared along with the successor and\nthe deprecation time when the version goes back into production.",
+                    "type": "string",
+                    "example": "2027-01-31T00:00:00Z"
+                },
                 "tags": {
-                    "type": "string"
+                    "description": "Tags are the labels rendered as comma separated key=value pairs for old clients, they are derived from the labels\nand can not be written, a body with other tags than those of its labels is rejected.",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "tier=1,pci"
                 },
                 "totalVersion": {
                     "type": "integer"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.0"
+                }
+            }
+        },
+        "Team": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "payments-platform"
+                },
+                "owners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Owner"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "VersionDiff": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "1.0.0"
+                },
+                "patch": {
+                    "description": "Patch turns the fields of the from version into those of the to version.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/JSONPatchOperation"
+                    }
+                },
+                "serviceName": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2.0.0"
+                },
+                "unified": {
+                    "description": "Unified renders the fields of both versions as a unified diff, empty when they do not differ.",
+                    "type": "string"
                 }
             }
         }
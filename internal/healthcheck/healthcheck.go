@@ -3,6 +3,7 @@ package healthcheck
 import (
 	"context"
 	"errors"
+	"github.com/suyog1pathak/services/pkg/config"
 	"github.com/suyog1pathak/services/pkg/datastore"
 	customerror "github.com/suyog1pathak/services/pkg/errors/service"
 	"time"
@@ -29,7 +30,8 @@ func checkDBconnection() bool {
 }
 
 func CheckHealthCheckStatus() error {
-	dbCheck := checkDBconnection()
+	// there is no database to check when running on the in memory datastore.
+	dbCheck := config.GetConfig().App.Datastore == "memory" || checkDBconnection()
 
 	if !dbCheck {
 		return errors.New(customerror.ErrHealthcheckDbFailed)
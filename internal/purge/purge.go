@@ -0,0 +1,139 @@
+package purge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"github.com/suyog1pathak/services/pkg/model"
+	"time"
+)
+
+const (
+	// defaultBatchSize is the number of rows deleted per statement unless configured.
+	defaultBatchSize = 500
+	// purgerActor is recorded for the purges which are not requested through the api.
+	purgerActor = "purger"
+)
+
+// Purger hard deletes service versions which have been soft deleted for longer than the retention window.
+type Purger struct {
+	repo      model.ServiceRepository
+	retention time.Duration
+	batchSize int
+	now       func() time.Time
+	actor     string
+	requestID string
+}
+
+// NewPurger returns a purger for the given retention window, a zero retention disables purging.
+func NewPurger(repo model.ServiceRepository, retention time.Duration, batchSize int) *Purger {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &Purger{repo: repo, retention: retention, batchSize: batchSize, now: time.Now, actor: purgerActor}
+}
+
+// As returns a copy of the purger recording the versions it purges in the audit log as purged by the actor
+// in the request.
+func (p *Purger) As(actor, requestID string) *Purger {
+	c := *p
+	c.actor, c.requestID = actor, requestID
+	if c.actor == "" {
+		c.actor = model.AnonymousActor
+	}
+	return &c
+}
+
+// Enabled reports whether a retention window is configured.
+func (p *Purger) Enabled() bool {
+	return p.retention > 0
+}
+
+// Run purges the expired tombstones in batches. A dry run only reports the versions which would be purged.
+func (p *Purger) Run(dryRun bool) (apiv1.PurgeReport, error) {
+	report := apiv1.PurgeReport{DryRun: dryRun}
+	if !p.Enabled() {
+		return report, errors.New(customerrors.ErrPurgeDisabled)
+	}
+	report.Cutoff = p.now().Add(-p.retention)
+
+	if dryRun {
+		expired, err := p.repo.ListDeletedBefore(report.Cutoff)
+		if err != nil {
+			return report, errors.New(customerrors.ErrInternalServer)
+		}
+		for _, s := range expired {
+			report.Services = append(report.Services, apiv1.PurgedService{Name: s.Name, Version: s.Version, DeletedAt: s.DeletedAt.Time})
+		}
+		report.Purged = int64(len(expired))
+		return report, nil
+	}
+
+	for {
+		purged, err := p.purge(report.Cutoff)
+		if err != nil {
+			return report, errors.New(customerrors.ErrInternalServer)
+		}
+		report.Purged += purged
+		if purged < int64(p.batchSize) {
+			break
+		}
+	}
+	log.Info("purged deleted services", "cutoff", report.Cutoff, "purged", report.Purged)
+	return report, nil
+}
+
+// purge purges a batch of expired tombstones and audits them in the same transaction, it returns their number.
+func (p *Purger) purge(cutoff time.Time) (int64, error) {
+	var purged int64
+	err := p.repo.Transaction(func(repo model.ServiceRepository) error {
+		services, err := repo.Purge(cutoff, p.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, s := range services {
+			before, err := json.Marshal(apiv1.PurgedService{Name: s.Name, Version: s.Version, DeletedAt: s.DeletedAt.Time})
+			if err != nil {
+				return err
+			}
+			err = repo.AddAuditEvent(&model.AuditEvent{
+				Actor:     p.actor,
+				Action:    model.AuditPurge,
+				Service:   s.Name,
+				Version:   s.Version,
+				Before:    before,
+				RequestID: p.requestID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		purged = int64(len(services))
+		return nil
+	})
+	return purged, err
+}
+
+// Start runs a purge every interval until the context is done.
+func (p *Purger) Start(ctx context.Context, interval time.Duration) {
+	if !p.Enabled() || interval <= 0 {
+		log.Info("background purge of deleted services is disabled")
+		return
+	}
+	log.Info("starting background purge of deleted services", "retention", p.retention, "interval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := p.Run(false); err != nil {
+				log.Error("error in purging deleted services", "error", err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package purge
+
+import (
+	"github.com/stretchr/testify/assert"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"github.com/suyog1pathak/services/pkg/semver"
+	"testing"
+	"time"
+)
+
+func addVersions(t *testing.T, repo model.ServiceRepository, name string, versions ...string) {
+	for _, v := range versions {
+		s := &model.Service{Name: name}
+		s.SetVersion(semver.MustParse(v))
+		assert.NoError(t, repo.Add(s))
+	}
+	assert.NoError(t, repo.SetCurrentVersion(model.NewCurrentVersion(name, semver.MustParse(versions[0]))))
+}
+
+func TestRunShouldPurgeExpiredTombstonesInBatches(t *testing.T) {
+	repo := model.NewMemoryServiceRepository()
+	addVersions(t, repo, "payments", "1.0.0", "2.0.0", "3.0.0")
+	addVersions(t, repo, "orders", "1.0.0", "2.0.0")
+	assert.NoError(t, repo.DeleteByName("payments"))
+	assert.NoError(t, repo.DeleteByNameAndVersion("orders", "2.0.0"))
+
+	p := NewPurger(repo, time.Hour, 2)
+	report, err := p.Run(true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), report.Purged)
+
+	p.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	report, err = p.Run(true)
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, int64(4), report.Purged)
+	assert.Len(t, report.Services, 4)
+	deleted, _ := repo.ListDeleted()
+	assert.Len(t, deleted, 4)
+
+	report, err = p.Run(false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), report.Purged)
+	assert.Empty(t, report.Services)
+	deleted, _ = repo.ListDeleted()
+	assert.Empty(t, deleted)
+
+	// the pointer of a service without any row left goes with it.
+	_, err = repo.GetCurrentVersion("payments")
+	assert.EqualError(t, err, customerrors.ErrCurrentVersionNotFound)
+	current, err := repo.GetCurrentVersion("orders")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", current.Version)
+}
+
+func TestRunShouldAuditPurgedVersions(t *testing.T) {
+	repo := model.NewMemoryServiceRepository()
+	addVersions(t, repo, "payments", "1.0.0", "2.0.0")
+	assert.NoError(t, repo.DeleteByNameAndVersion("payments", "2.0.0"))
+
+	p := NewPurger(repo, time.Hour, 0)
+	p.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err := p.As("jane.doe", "req-1").Run(true)
+	assert.NoError(t, err)
+	events, _ := repo.GetAuditEvents(model.AuditSearch{Limit: 10})
+	assert.Empty(t, events)
+
+	_, err = p.As("jane.doe", "req-1").Run(false)
+	assert.NoError(t, err)
+	events, _ = repo.GetAuditEvents(model.AuditSearch{Limit: 10})
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, model.AuditPurge, events[0].Action)
+		assert.Equal(t, "jane.doe", events[0].Actor)
+		assert.Equal(t, "req-1", events[0].RequestID)
+		assert.Equal(t, "payments", events[0].Service)
+		assert.Equal(t, "2.0.0", events[0].Version)
+		assert.Contains(t, string(events[0].Before), `"deletedAt"`)
+	}
+
+	// purges run in the background are audited as the purger's.
+	assert.NoError(t, repo.DeleteByNameAndVersion("payments", "1.0.0"))
+	_, err = p.Run(false)
+	assert.NoError(t, err)
+	events, _ = repo.GetAuditEvents(model.AuditSearch{SinceID: events[0].ID, Limit: 10})
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, purgerActor, events[0].Actor)
+		assert.Equal(t, "1.0.0", events[0].Version)
+	}
+}
+
+func TestRunShouldFailWithoutRetention(t *testing.T) {
+	_, err := NewPurger(model.NewMemoryServiceRepository(), 0, 0).Run(true)
+	assert.EqualError(t, err, customerrors.ErrPurgeDisabled)
+}
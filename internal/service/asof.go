@@ -0,0 +1,304 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"github.com/suyog1pathak/services/pkg/semver"
+	"gorm.io/gorm"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+// AsOf returns a read only copy of the service answering with the named service as it was at the RFC3339 time asOf.
+// Without a time it returns the service itself.
+//
+// The versions live at the time are read from their creation and deletion times, less the ones the audit log
+// tells were deleted then and restored since, their fields are the ones recorded before their first update since
+// in the audit log. Current versions are replayed from the last promotion, rollback or restore before the time,
+// releases published after it become current when they are higher. Times before the deletion of a version
+// purged since fail with ErrAsOfUnavailable.
+func (s *Service) AsOf(asOf, name string) (*Service, error) {
+	if asOf == "" {
+		return s, nil
+	}
+	at, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		return nil, errors.New(customerrors.ErrInvalidAsOf)
+	}
+	if err = s.checkPurged(at, []string{name}); err != nil {
+		return nil, err
+	}
+	return s.asOf(at, []string{name})
+}
+
+// SearchAndSortAsOf lists a page of the catalog as it was at the RFC3339 time asOf. Only the services of the page are
+// rebuilt, so the listing is sorted by name and can not be searched nor read by cursor, ErrInvalidAsOfListing otherwise.
+// The names of the page are picked from the creation and deletion times of the versions, the listing fails with
+// ErrAsOfUnavailable when they are not enough, see checkPurged and checkRestored.
+func (s *Service) SearchAndSortAsOf(asOf string, search model.ServiceSearch, sort model.ServiceSort, page, pageSize int, token string) (apiv1.ServicePagination, error) {
+	at, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		return apiv1.ServicePagination{}, errors.New(customerrors.ErrInvalidAsOf)
+	}
+	searched := search.Query != "" || len(search.Owners) > 0 || len(search.Labels) > 0 || len(search.Lifecycles) > 0
+	if searched || len(sort) != 1 || sort[0].Key != model.SortName || token != "" {
+		return apiv1.ServicePagination{}, errors.New(customerrors.ErrInvalidAsOfListing)
+	}
+	if err = s.checkPurged(at, nil); err != nil {
+		return apiv1.ServicePagination{}, err
+	}
+	if err = s.checkRestored(at); err != nil {
+		return apiv1.ServicePagination{}, err
+	}
+	names, total, err := s.repo.GetLiveNamesAt(at, sort[0].Desc, model.ServicePage{Limit: pageSize, Offset: (page - 1) * pageSize})
+	if err != nil {
+		return apiv1.ServicePagination{}, err
+	}
+	// same as a page past the end of the listing.
+	if len(names) == 0 {
+		return apiv1.ServicePagination{}, errors.New(customerrors.ErrServiceNotFound)
+	}
+	snapshot, err := s.asOf(at, names)
+	if err != nil {
+		return apiv1.ServicePagination{}, err
+	}
+	response, err := snapshot.SearchAndSort(model.ServiceSearch{}, sort, 1, pageSize, "")
+	if err != nil {
+		return apiv1.ServicePagination{}, err
+	}
+	response.Meta = apiv1.Meta{
+		Page:         page,
+		PageSize:     pageSize,
+		TotalResults: int(total),
+		TotalPages:   int(math.Ceil(float64(total) / float64(pageSize))),
+	}
+	return response, nil
+}
+
+// asOf returns a read only copy of the service answering with the named services as they were at the time.
+func (s *Service) asOf(at time.Time, names []string) (*Service, error) {
+	versions, err := s.repo.GetLiveAt(names, at)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.deletedAt(at, names)
+	if err != nil {
+		return nil, err
+	}
+	versions = slices.DeleteFunc(versions, func(v model.Service) bool { return deleted[v.Name][v.Version] })
+	// the first update after the time recorded the version as it was.
+	updates, err := s.repo.GetFirstAuditEvents(model.AuditSearch{
+		Services: names,
+		Actions:  []string{model.AuditUpdateVersion},
+		From:     at.Add(time.Nanosecond),
+	})
+	if err != nil {
+		return nil, err
+	}
+	before := map[string]json.RawMessage{}
+	for _, e := range updates {
+		before[e.Service+"@"+e.Version] = e.Before
+	}
+	for i, v := range versions {
+		if b, ok := before[v.Name+"@"+v.Version]; ok {
+			var state model.Service
+			if err := json.Unmarshal(b, &state); err != nil {
+				return nil, err
+			}
+			state.SetVersion(v.SemVer())
+			versions[i] = state
+		}
+		versions[i].DeletedAt = gorm.DeletedAt{}
+	}
+
+	pointers, err := s.repo.GetLastAuditEvents(model.AuditSearch{
+		Services: names,
+		Actions:  []string{model.AuditPromote, model.AuditRollback, model.AuditRestore},
+		To:       at.Add(time.Nanosecond),
+	})
+	if err != nil {
+		return nil, err
+	}
+	snapshot := *s
+	snapshot.repo = model.NewMemoryServiceRepositoryOf(versions, currentVersionsAt(versions, pointers))
+	return &snapshot, nil
+}
+
+// deletedAt replays the creations, deletions and restores of the named services in the audit log up to the time and
+// returns the versions which were deleted then by service. The rows of the versions only keep their last deletion,
+// a version restored since reads as live all along from them.
+func (s *Service) deletedAt(at time.Time, names []string) (map[string]map[string]bool, error) {
+	events, err := s.allAuditEvents(model.AuditSearch{
+		Services: names,
+		Actions:  []string{model.AuditCreate, model.AuditCreateVersion, model.AuditDelete, model.AuditDeleteVersion, model.AuditRestore},
+		To:       at.Add(time.Nanosecond),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the versions deleted at the time of the events so far with the time they were deleted at.
+	deletions := map[string]map[string]time.Time{}
+	for _, e := range events {
+		versions, ok := deletions[e.Service]
+		if !ok {
+			versions = map[string]time.Time{}
+			deletions[e.Service] = versions
+		}
+		switch e.Action {
+		case model.AuditCreate, model.AuditCreateVersion:
+			delete(versions, e.Version)
+		case model.AuditDeleteVersion:
+			versions[e.Version] = e.CreatedAt
+		case model.AuditDelete:
+			var before []struct {
+				Version string `json:"version"`
+			}
+			if err := json.Unmarshal(e.Before, &before); err != nil {
+				return nil, err
+			}
+			for _, v := range before {
+				versions[v.Version] = e.CreatedAt
+			}
+		case model.AuditRestore:
+			if e.Version != "" {
+				delete(versions, e.Version)
+				continue
+			}
+			// restoring the service restores the versions it was deleted with, the ones deleted last.
+			var last time.Time
+			for _, t := range versions {
+				if t.After(last) {
+					last = t
+				}
+			}
+			maps.DeleteFunc(versions, func(_ string, t time.Time) bool { return t.Equal(last) })
+		}
+	}
+	output := map[string]map[string]bool{}
+	for name, versions := range deletions {
+		output[name] = map[string]bool{}
+		for v := range versions {
+			output[name][v] = true
+		}
+	}
+	return output, nil
+}
+
+// checkPurged fails with ErrAsOfUnavailable when the time is before the deletion of a version of the named services,
+// of any service without names, which has been purged since, the version may have been live then. Tombstones are
+// purged oldest first, the last purge of a service is of the version deleted last.
+func (s *Service) checkPurged(at time.Time, names []string) error {
+	purges, err := s.repo.GetLastAuditEvents(model.AuditSearch{Services: names, Actions: []string{model.AuditPurge}})
+	if err != nil {
+		return err
+	}
+	for _, e := range purges {
+		var before apiv1.PurgedService
+		if err := json.Unmarshal(e.Before, &before); err != nil {
+			return err
+		}
+		if before.DeletedAt.After(at) {
+			return errors.New(customerrors.ErrAsOfUnavailable)
+		}
+	}
+	return nil
+}
+
+// checkRestored fails with ErrAsOfUnavailable when a service had all of its versions deleted at the time and one of
+// them restored since, the rows the names of a listing are picked from read the service as live then.
+func (s *Service) checkRestored(at time.Time) error {
+	restores, err := s.allAuditEvents(model.AuditSearch{Actions: []string{model.AuditRestore}, From: at.Add(time.Nanosecond)})
+	if err != nil || len(restores) == 0 {
+		return err
+	}
+	var names []string
+	for _, e := range restores {
+		if !slices.Contains(names, e.Service) {
+			names = append(names, e.Service)
+		}
+	}
+	versions, err := s.repo.GetLiveAt(names, at)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.deletedAt(at, names)
+	if err != nil {
+		return err
+	}
+	listed, live := map[string]bool{}, map[string]bool{}
+	for _, v := range versions {
+		listed[v.Name] = true
+		live[v.Name] = live[v.Name] || !deleted[v.Name][v.Version]
+	}
+	for name := range listed {
+		if !live[name] {
+			return errors.New(customerrors.ErrAsOfUnavailable)
+		}
+	}
+	return nil
+}
+
+// allAuditEvents returns every event matching the search, it is read a page at a time.
+func (s *Service) allAuditEvents(search model.AuditSearch) ([]model.AuditEvent, error) {
+	search.Limit = maxAuditLimit
+	var output []model.AuditEvent
+	for {
+		events, err := s.repo.GetAuditEvents(search)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, events...)
+		if len(events) < search.Limit {
+			return output, nil
+		}
+		search.SinceID = events[len(events)-1].ID
+	}
+}
+
+// currentVersionsAt returns the current versions of the services given their live versions and their last promotion,
+// rollback or restore before the time.
+func currentVersionsAt(versions []model.Service, pointers []model.AuditEvent) []model.CurrentVersion {
+	type pointer struct {
+		version string
+		at      time.Time
+	}
+	last := map[string]pointer{}
+	for _, e := range pointers {
+		var after struct {
+			CurrentVersion string `json:"currentVersion"`
+		}
+		if err := json.Unmarshal(e.After, &after); err == nil && after.CurrentVersion != "" {
+			last[e.Service] = pointer{version: after.CurrentVersion, at: e.CreatedAt}
+		}
+	}
+
+	var names []string
+	live := map[string][]model.Service{}
+	for _, v := range versions {
+		if _, ok := live[v.Name]; !ok {
+			names = append(names, v.Name)
+		}
+		live[v.Name] = append(live[v.Name], v)
+	}
+	output := make([]model.CurrentVersion, 0, len(names))
+	for _, name := range names {
+		// without a pointer event, or when the version pointed at was deleted since, the highest release is current.
+		current := highestRelease(live[name])
+		if p, ok := last[name]; ok && slices.ContainsFunc(live[name], func(v model.Service) bool { return v.Version == p.version }) {
+			current = semver.MustParse(p.version)
+			// releases published after the event became current when they were higher.
+			for _, v := range live[name] {
+				if sv := v.SemVer(); sv.Prerelease == "" && v.CreatedAt.After(p.at) && current.Less(sv) {
+					current = sv
+				}
+			}
+		}
+		output = append(output, *model.NewCurrentVersion(name, current))
+	}
+	return output
+}
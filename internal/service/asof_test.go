@@ -0,0 +1,192 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/suyog1pathak/services/internal/purge"
+	"github.com/suyog1pathak/services/pkg/cursor"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"testing"
+	"time"
+)
+
+// moment returns the current time as an as_of param, the changes made after it are made strictly later.
+func moment() string {
+	defer time.Sleep(5 * time.Millisecond)
+	return time.Now().Format(time.RFC3339Nano)
+}
+
+func TestAsOfShouldAnswerWithThePastCatalog(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments", Description: "Payments", Labels: model.Labels{{Key: "tier", Value: "1"}}})
+	_, _ = s.Create(&model.Service{Name: "ledger"})
+	then := moment()
+
+	_, err := s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Description: "Card payments", Labels: model.Labels{}})
+	assert.NoError(t, err)
+	_, _ = s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Lifecycle: model.LifecycleDeprecated})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments"})
+	assert.NoError(t, s.Delete("ledger"))
+	_, _ = s.Create(&model.Service{Name: "checkout"})
+
+	page, err := s.SearchAndSortAsOf(then, model.ServiceSearch{}, model.ServiceSort{{Key: model.SortName}}, 1, 10, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ledger", "payments"}, names(page.Data))
+	assert.Equal(t, "Payments", page.Data[1].Description)
+
+	past, err := s.AsOf(then, "payments")
+	assert.NoError(t, err)
+	versions, err := past.FetchByName("payments")
+	assert.NoError(t, err)
+	assert.Len(t, versions, 1)
+	assert.Equal(t, "Payments", versions[0].Description)
+	assert.Equal(t, model.LifecycleProduction, versions[0].Lifecycle)
+	assert.Equal(t, "tier=1", versions[0].Tags)
+	current, err := past.FetchCurrent("payments")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", current.Version)
+
+	now, err := s.AsOf(moment(), "payments")
+	assert.NoError(t, err)
+	version, err := now.FetchByVersionAndName("payments", "1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "Card payments", version.Description)
+	assert.Equal(t, model.LifecycleDeprecated, version.Lifecycle)
+	current, _ = now.FetchCurrent("payments")
+	assert.Equal(t, "2.0.0", current.Version)
+
+	_, err = s.AsOf("yesterday", "payments")
+	assert.EqualError(t, err, customerrors.ErrInvalidAsOf)
+}
+
+func TestSearchAndSortAsOfShouldRebuildOnlyThePage(t *testing.T) {
+	s := newTestService()
+	for _, name := range []string{"checkout", "ledger", "payments", "fraud"} {
+		_, _ = s.Create(&model.Service{Name: name})
+	}
+	then := moment()
+	_, _ = s.Create(&model.Service{Name: "auth"})
+	assert.NoError(t, s.Delete("fraud"))
+
+	byName := model.ServiceSort{{Key: model.SortName}}
+	page, err := s.SearchAndSortAsOf(then, model.ServiceSearch{}, byName, 2, 2, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ledger", "payments"}, names(page.Data))
+	assert.Equal(t, 4, page.Meta.TotalResults)
+	assert.Equal(t, 2, page.Meta.TotalPages)
+	page, err = s.SearchAndSortAsOf(then, model.ServiceSearch{}, model.ServiceSort{{Key: model.SortName, Desc: true}}, 1, 3, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"payments", "ledger", "fraud"}, names(page.Data))
+	_, err = s.SearchAndSortAsOf(then, model.ServiceSearch{}, byName, 3, 2, "")
+	assert.EqualError(t, err, customerrors.ErrServiceNotFound)
+
+	// the whole catalog would have to be rebuilt to search or sort it otherwise.
+	for _, listing := range []struct {
+		search model.ServiceSearch
+		sort   model.ServiceSort
+		cursor string
+	}{
+		{search: model.ServiceSearch{Query: "pay"}, sort: byName},
+		{search: model.ServiceSearch{Owners: []string{"core"}}, sort: byName},
+		{sort: model.ServiceSort{{Key: model.SortCreatedAt}}},
+		{sort: model.ServiceSort{{Key: model.SortName}, {Key: model.SortUpdatedAt}}},
+		{sort: byName, cursor: "next"},
+	} {
+		_, err = s.SearchAndSortAsOf(then, listing.search, listing.sort, 1, 10, listing.cursor)
+		assert.EqualError(t, err, customerrors.ErrInvalidAsOfListing)
+	}
+}
+
+func TestAsOfShouldReplayCurrentVersions(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments", Version: "3.0.0-rc.1"})
+	beforeRollback := moment()
+	_, err := s.Rollback("payments")
+	assert.NoError(t, err)
+	rolledBack := moment()
+	_, _ = s.Promote("payments", "3.0.0-rc.1")
+	promoted := moment()
+	_, _ = s.CreateVersion(&model.Service{Name: "payments", Version: "1.1.0"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments", Version: "3.0.0"})
+
+	for at, want := range map[string]string{
+		beforeRollback: "2.0.0",
+		rolledBack:     "1.0.0",
+		promoted:       "3.0.0-rc.1",
+		moment():       "3.0.0",
+	} {
+		past, err := s.AsOf(at, "payments")
+		assert.NoError(t, err)
+		current, err := past.FetchCurrent("payments")
+		assert.NoError(t, err)
+		assert.Equal(t, want, current.Version, at)
+	}
+}
+
+func TestAsOfShouldNotReadVersionsRestoredSinceAsLive(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments"})
+	_, _ = s.Create(&model.Service{Name: "ledger"})
+	assert.NoError(t, s.DeleteVersion("payments", "2.0.0", true))
+	assert.NoError(t, s.Delete("ledger"))
+	deleted := moment()
+	_, err := s.Restore("payments", "2.0.0")
+	assert.NoError(t, err)
+	_, err = s.Restore("ledger", "")
+	assert.NoError(t, err)
+	restored := moment()
+
+	past, err := s.AsOf(deleted, "payments")
+	assert.NoError(t, err)
+	versions, err := past.FetchByName("payments")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0"}, versionsOf(versions))
+	past, err = s.AsOf(deleted, "ledger")
+	assert.NoError(t, err)
+	_, err = past.FetchByName("ledger")
+	assert.EqualError(t, err, customerrors.ErrServiceNotFound)
+
+	// the names of the listing would include ledger.
+	byName := model.ServiceSort{{Key: model.SortName}}
+	_, err = s.SearchAndSortAsOf(deleted, model.ServiceSearch{}, byName, 1, 10, "")
+	assert.EqualError(t, err, customerrors.ErrAsOfUnavailable)
+	page, err := s.SearchAndSortAsOf(restored, model.ServiceSearch{}, byName, 1, 10, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ledger", "payments"}, names(page.Data))
+	past, _ = s.AsOf(restored, "payments")
+	versions, _ = past.FetchByName("payments")
+	assert.Equal(t, []string{"1.0.0", "2.0.0"}, versionsOf(versions))
+}
+
+func TestAsOfShouldRejectTimesBeforeTheDeletionOfAPurgedVersion(t *testing.T) {
+	repo := model.NewMemoryServiceRepository()
+	s := NewService(repo, model.NewMemoryTeamRepository(), cursor.NewCodec([]byte("secret")))
+	_, _ = s.Create(&model.Service{Name: "payments"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments"})
+	_, _ = s.Create(&model.Service{Name: "ledger"})
+	then := moment()
+	assert.NoError(t, s.DeleteVersion("payments", "2.0.0", true))
+	deleted := moment()
+	_, err := purge.NewPurger(repo, time.Nanosecond, 0).Run(false)
+	assert.NoError(t, err)
+
+	_, err = s.AsOf(then, "payments")
+	assert.EqualError(t, err, customerrors.ErrAsOfUnavailable)
+	_, err = s.SearchAndSortAsOf(then, model.ServiceSearch{}, model.ServiceSort{{Key: model.SortName}}, 1, 10, "")
+	assert.EqualError(t, err, customerrors.ErrAsOfUnavailable)
+	_, err = s.AsOf(then, "ledger")
+	assert.NoError(t, err)
+	_, err = s.AsOf(deleted, "payments")
+	assert.NoError(t, err)
+}
+
+func versionsOf(services []model.Service) []string {
+	var output []string
+	for _, s := range services {
+		output = append(output, s.Version)
+	}
+	return output
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"github.com/suyog1pathak/services/pkg/util"
+	"time"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// Caller is who changes are made by, it is recorded in the audit log along with the request they are made in.
+type Caller struct {
+	Actor     string
+	RequestID string
+}
+
+// As returns a copy of the service recording the changes it makes as made by the caller.
+func (s *Service) As(caller Caller) *Service {
+	c := *s
+	c.caller = caller
+	return &c
+}
+
+// FetchAudit returns a page of the audit log of the service or team, or of everything when both are empty,
+// filtered by actor and by the RFC3339 times from and to.
+func (s *Service) FetchAudit(name, team, actor, from, to, sinceID, limit string) (apiv1.AuditLog, error) {
+	search := model.AuditSearch{Service: name, Team: team, Actor: actor, Limit: defaultAuditLimit}
+	var err error
+	if search.From, err = parseAuditTime(from); err != nil {
+		return apiv1.AuditLog{}, err
+	}
+	if search.To, err = parseAuditTime(to); err != nil {
+		return apiv1.AuditLog{}, err
+	}
+	if !search.From.IsZero() && !search.To.IsZero() && !search.From.Before(search.To) {
+		return apiv1.AuditLog{}, errors.New(customerrors.ErrInvalidAuditSearch)
+	}
+	if sinceID != "" {
+		id, err := util.StringToInt(sinceID)
+		if err != nil || id < 0 {
+			return apiv1.AuditLog{}, errors.New(customerrors.ErrInvalidAuditSearch)
+		}
+		search.SinceID = uint(id)
+	}
+	if limit != "" {
+		search.Limit, err = util.StringToInt(limit)
+		if err != nil || search.Limit < 1 || search.Limit > maxAuditLimit {
+			return apiv1.AuditLog{}, errors.New(customerrors.ErrInvalidAuditSearch)
+		}
+	}
+	// one more event than asked for tells whether there is a next page.
+	search.Limit++
+	events, err := s.repo.GetAuditEvents(search)
+	if err != nil {
+		return apiv1.AuditLog{}, err
+	}
+	response := apiv1.AuditLog{Events: events}
+	if len(events) == search.Limit {
+		response.Events = events[:len(events)-1]
+		response.NextSinceID = response.Events[len(response.Events)-1].ID
+	}
+	return response, nil
+}
+
+// transaction runs fn with a copy of the service whose repository writes in a single transaction,
+// so a change and its audit event are stored together or not at all.
+func (s *Service) transaction(fn func(tx *Service) error) error {
+	return s.repo.Transaction(func(repo model.ServiceRepository) error {
+		return s.teams.Transaction(repo, func(teams model.TeamRepository) error {
+			tx := *s
+			tx.repo, tx.teams = repo, teams
+			return fn(&tx)
+		})
+	})
+}
+
+// audit appends the change to the audit log, before and after are stored as JSON.
+func (s *Service) audit(action, name, version string, before, after interface{}) error {
+	return s.record(&model.AuditEvent{Action: action, Service: name, Version: version}, before, after)
+}
+
+// auditTeam appends the change made to the team to the audit log like audit.
+func (s *Service) auditTeam(action, team string, before, after interface{}) error {
+	return s.record(&model.AuditEvent{Action: action, Team: team}, before, after)
+}
+
+// record appends the event as made by the caller.
+func (s *Service) record(event *model.AuditEvent, before, after interface{}) error {
+	event.Actor, event.RequestID = s.caller.Actor, s.caller.RequestID
+	if event.Actor == "" {
+		event.Actor = model.AnonymousActor
+	}
+	var err error
+	if event.Before, err = auditJSON(before); err != nil {
+		return err
+	}
+	if event.After, err = auditJSON(after); err != nil {
+		return err
+	}
+	return s.repo.AddAuditEvent(event)
+}
+
+// snapshot describes the service by its current version for the audit log, nil when it has no live versions.
+func (s *Service) snapshot(name string) (interface{}, error) {
+	details, err := s.serviceDetails(name)
+	if err != nil {
+		if err.Error() == customerrors.ErrServiceNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return details, nil
+}
+
+// auditJSON marshals what a change touched, nil is stored as null.
+func auditJSON(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+func parseAuditTime(t string) (time.Time, error) {
+	if t == "" {
+		return time.Time{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, t)
+	if err != nil {
+		return time.Time{}, errors.New(customerrors.ErrInvalidAuditSearch)
+	}
+	return parsed, nil
+}
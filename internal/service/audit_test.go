@@ -0,0 +1,136 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestMutationsShouldBeAudited(t *testing.T) {
+	s := newTestService()
+	jane := s.As(Caller{Actor: "jane.doe", RequestID: "req-1"})
+	_, err := jane.Create(&model.Service{Name: "payments", Description: "Payments"})
+	assert.NoError(t, err)
+	_, err = jane.CreateVersion(&model.Service{Name: "payments"})
+	assert.NoError(t, err)
+	_, err = s.UpdateVersion(&model.Service{Name: "payments", Version: "1", Description: "Card payments"})
+	assert.NoError(t, err)
+	_, err = jane.Rollback("payments")
+	assert.NoError(t, err)
+	assert.NoError(t, jane.Delete("payments"))
+
+	log, err := s.FetchAudit("", "", "", "", "", "", "")
+	assert.NoError(t, err)
+	var actions, actors, versions []string
+	for _, e := range log.Events {
+		actions = append(actions, e.Action)
+		actors = append(actors, e.Actor)
+		versions = append(versions, e.Version)
+		assert.Equal(t, "payments", e.Service)
+	}
+	assert.Equal(t, []string{model.AuditCreate, model.AuditCreateVersion, model.AuditUpdateVersion, model.AuditRollback, model.AuditDelete}, actions)
+	assert.Equal(t, []string{"jane.doe", "jane.doe", model.AnonymousActor, "jane.doe", "jane.doe"}, actors)
+	assert.Equal(t, []string{"1.0.0", "2.0.0", "1.0.0", "1.0.0", ""}, versions)
+	assert.Equal(t, "req-1", log.Events[0].RequestID)
+	assert.Zero(t, log.NextSinceID)
+
+	update := log.Events[2]
+	var before, after model.Service
+	assert.NoError(t, json.Unmarshal(update.Before, &before))
+	assert.NoError(t, json.Unmarshal(update.After, &after))
+	assert.Equal(t, "Payments", before.Description)
+	assert.Equal(t, "Card payments", after.Description)
+	assert.Nil(t, log.Events[0].Before)
+	assert.Nil(t, log.Events[4].After)
+	var deleted []model.Service
+	assert.NoError(t, json.Unmarshal(log.Events[4].Before, &deleted))
+	assert.Len(t, deleted, 2)
+}
+
+func TestFailedMutationShouldNotBeAudited(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+
+	err := s.DeleteVersion("payments", "1.0.0", false)
+	assert.EqualError(t, err, customerrors.ErrCurrentVersionDelete)
+	_, err = s.Promote("payments", "3.0.0")
+	assert.Error(t, err)
+
+	log, err := s.FetchAudit("payments", "", "", "", "", "", "")
+	assert.NoError(t, err)
+	assert.Len(t, log.Events, 1)
+}
+
+func TestTransactionShouldRollBackOnError(t *testing.T) {
+	repo := model.NewMemoryServiceRepository()
+	err := repo.Transaction(func(tx model.ServiceRepository) error {
+		assert.NoError(t, tx.Add(&model.Service{Name: "payments", Version: "1.0.0"}))
+		assert.NoError(t, tx.AddAuditEvent(&model.AuditEvent{Action: model.AuditCreate, Service: "payments"}))
+		return errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+
+	_, err = repo.GetByName("payments")
+	assert.EqualError(t, err, customerrors.ErrServiceNotFound)
+	events, err := repo.GetAuditEvents(model.AuditSearch{Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestRollbackShouldKeepTheWritesMadeOutsideOfTheTransaction(t *testing.T) {
+	repo := model.NewMemoryServiceRepository()
+	added := make(chan error)
+	err := repo.Transaction(func(tx model.ServiceRepository) error {
+		assert.NoError(t, tx.Add(&model.Service{Name: "payments", Version: "1.0.0"}))
+		go func() { added <- repo.Add(&model.Service{Name: "ledger", Version: "1.0.0"}) }()
+		// the write outside of the transaction waits for it rather than being rolled back with it.
+		time.Sleep(10 * time.Millisecond)
+		return errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+	assert.NoError(t, <-added)
+
+	_, err = repo.GetByName("payments")
+	assert.EqualError(t, err, customerrors.ErrServiceNotFound)
+	ledger, err := repo.GetByName("ledger")
+	assert.NoError(t, err)
+	assert.Len(t, ledger, 1)
+}
+
+func TestFetchAuditShouldFilterAndPage(t *testing.T) {
+	s := newTestService()
+	_, _ = s.As(Caller{Actor: "jane.doe"}).Create(&model.Service{Name: "payments"})
+	_, _ = s.As(Caller{Actor: "john.roe"}).Create(&model.Service{Name: "ledger"})
+	_, _ = s.As(Caller{Actor: "jane.doe"}).CreateVersion(&model.Service{Name: "ledger"})
+
+	log, err := s.FetchAudit("", "", "jane.doe", "", "", "", "1")
+	assert.NoError(t, err)
+	assert.Len(t, log.Events, 1)
+	assert.Equal(t, "payments", log.Events[0].Service)
+	assert.Equal(t, log.Events[0].ID, log.NextSinceID)
+
+	log, err = s.FetchAudit("", "", "jane.doe", "", "", "1", "1")
+	assert.NoError(t, err)
+	assert.Len(t, log.Events, 1)
+	assert.Equal(t, "ledger", log.Events[0].Service)
+	assert.Zero(t, log.NextSinceID)
+
+	log, err = s.FetchAudit("ledger", "", "", "", "", "", "")
+	assert.NoError(t, err)
+	assert.Len(t, log.Events, 2)
+
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	log, err = s.FetchAudit("", "", "", future, "", "", "")
+	assert.NoError(t, err)
+	assert.Empty(t, log.Events)
+
+	for _, params := range [][]string{{"yesterday", ""}, {future, future}, {"", "", "-1"}, {"", "", "", "0"}, {"", "", "", "1001"}} {
+		params = append(params, "", "", "")
+		_, err = s.FetchAudit("", "", "", params[0], params[1], params[2], params[3])
+		assert.EqualError(t, err, customerrors.ErrInvalidAuditSearch, params)
+	}
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	"slices"
+	"sort"
+)
+
+// FetchChangelog returns the release notes of the live versions of the service, the most recently published first
+// and versions published at the same time by precedence, the highest first.
+func (s *Service) FetchChangelog(name string) (apiv1.Changelog, error) {
+	versions, err := s.FetchByName(name)
+	if err != nil {
+		return apiv1.Changelog{}, err
+	}
+	slices.Reverse(versions)
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i].CreatedAt.After(versions[j].CreatedAt) })
+	changelog := apiv1.Changelog{Service: name, Entries: []apiv1.ChangelogEntry{}}
+	for _, v := range versions {
+		changelog.Entries = append(changelog.Entries, apiv1.ChangelogEntry{
+			Version:   v.Version,
+			Author:    v.Author,
+			CreatedAt: v.CreatedAt,
+			Changelog: v.Changelog,
+		})
+	}
+	return changelog, nil
+}
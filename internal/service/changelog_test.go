@@ -0,0 +1,33 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/suyog1pathak/services/pkg/model"
+	"testing"
+)
+
+func TestFetchChangelogShouldListNewestFirst(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments", Author: "jane.doe", Changelog: "First release."})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments", Author: "john.roe", Changelog: "Adds refunds."})
+	// a backport published after 2.0.0 comes first.
+	_, _ = s.CreateVersion(&model.Service{Name: "payments", Version: "1.0.1", Author: "jane.doe"})
+
+	_, err := s.UpdateVersion(&model.Service{Name: "payments", Version: "2.0.0", Author: "someone.else", Changelog: "Adds refunds and chargebacks."})
+	assert.NoError(t, err)
+
+	changelog, err := s.FetchChangelog("payments")
+	assert.NoError(t, err)
+	var got [][3]string
+	for _, e := range changelog.Entries {
+		got = append(got, [3]string{e.Version, e.Author, e.Changelog})
+	}
+	assert.Equal(t, [][3]string{
+		{"1.0.1", "jane.doe", ""},
+		{"2.0.0", "john.roe", "Adds refunds and chargebacks."},
+		{"1.0.0", "jane.doe", "First release."},
+	}, got)
+
+	_, err = s.FetchChangelog("unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"errors"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"github.com/suyog1pathak/services/pkg/semver"
+	"github.com/suyog1pathak/services/pkg/util"
+	"sort"
+)
+
+// maxDependencyDepth is the deepest a dependency traversal goes.
+const maxDependencyDepth = 32
+
+// FetchDependencies returns the services the given version of the service depends on, its current version when
+// version is empty, down to depth levels. Dependencies further down are followed through their current versions.
+func (s *Service) FetchDependencies(name, version, depth string) (apiv1.DependencyGraph, error) {
+	levels, err := parseDepth(depth)
+	if err != nil {
+		return apiv1.DependencyGraph{}, err
+	}
+	if version == "" {
+		current, err := s.serviceDetails(name)
+		if err != nil {
+			return apiv1.DependencyGraph{}, err
+		}
+		version = current.CurrentVersion
+	}
+	root, err := s.FetchByVersionAndName(name, version)
+	if err != nil {
+		return apiv1.DependencyGraph{}, err
+	}
+	edges, err := s.repo.GetDependencyEdges()
+	if err != nil {
+		return apiv1.DependencyGraph{}, err
+	}
+	var first []model.DependencyEdge
+	current := map[string][]model.DependencyEdge{}
+	for _, e := range edges {
+		if e.Service == root.Name && e.Version == root.Version {
+			first = append(first, e)
+		}
+		if e.Current {
+			current[e.Service] = append(current[e.Service], e)
+		}
+	}
+	graph := apiv1.DependencyGraph{Service: root.Name, Version: root.Version, Depth: levels}
+	graph.Edges = traverse(root.Name, first, levels, func(e model.DependencyEdge) string { return e.Dependency }, current)
+	return graph, nil
+}
+
+// FetchDependents returns the services whose current version depends on the service, up to depth levels,
+// which is what breaks when the service goes down.
+func (s *Service) FetchDependents(name, depth string) (apiv1.DependencyGraph, error) {
+	levels, err := parseDepth(depth)
+	if err != nil {
+		return apiv1.DependencyGraph{}, err
+	}
+	root, err := s.serviceDetails(name)
+	if err != nil {
+		return apiv1.DependencyGraph{}, err
+	}
+	edges, err := s.repo.GetDependencyEdges()
+	if err != nil {
+		return apiv1.DependencyGraph{}, err
+	}
+	dependents := map[string][]model.DependencyEdge{}
+	for _, e := range edges {
+		if e.Current {
+			dependents[e.Dependency] = append(dependents[e.Dependency], e)
+		}
+	}
+	graph := apiv1.DependencyGraph{Service: name, Version: root.CurrentVersion, Depth: levels}
+	graph.Edges = traverse(name, dependents[name], levels, func(e model.DependencyEdge) string { return e.Service }, dependents)
+	return graph, nil
+}
+
+// FetchGraph returns the dependency graph of the current versions of the catalog, the services without any
+// dependency or dependent left out. When root is set only the dependencies, or the dependents, of the root
+// down to depth levels are part of the graph.
+func (s *Service) FetchGraph(root, direction, depth string) (apiv1.Graph, error) {
+	var edges []model.DependencyEdge
+	nodes := map[string]bool{}
+	switch {
+	case root == "":
+		all, err := s.repo.GetDependencyEdges()
+		if err != nil {
+			return apiv1.Graph{}, err
+		}
+		for _, e := range all {
+			if e.Current {
+				edges = append(edges, e)
+			}
+		}
+	case direction == "" || direction == "dependencies" || direction == "dependents":
+		var graph apiv1.DependencyGraph
+		var err error
+		if direction == "dependents" {
+			graph, err = s.FetchDependents(root, depth)
+		} else {
+			graph, err = s.FetchDependencies(root, "", depth)
+		}
+		if err != nil {
+			return apiv1.Graph{}, err
+		}
+		nodes[root] = true
+		for _, e := range graph.Edges {
+			edges = append(edges, e.DependencyEdge)
+		}
+	default:
+		return apiv1.Graph{}, errors.New(customerrors.ErrInvalidGraphDirection)
+	}
+	for _, e := range edges {
+		nodes[e.Service] = true
+		nodes[e.Dependency] = true
+	}
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	summaries, err := s.repo.GetSummaries(names)
+	if err != nil {
+		return apiv1.Graph{}, err
+	}
+	byName := map[string]model.ServiceSummary{}
+	for _, summary := range summaries {
+		byName[summary.Name] = summary
+	}
+	graph := apiv1.Graph{Edges: edges}
+	for _, name := range names {
+		node := apiv1.GraphNode{Name: name}
+		if summary, ok := byName[name]; ok {
+			node.Version = summary.Version
+			node.Owner = string(summary.Owner)
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	return graph, nil
+}
+
+// traverse walks the graph breadth first from the edges of the root, far returns the service an edge leads to
+// and edges the edges leading on from a service. Every service is followed once, at its shortest distance from the root.
+func traverse(root string, first []model.DependencyEdge, depth int, far func(model.DependencyEdge) string, edges map[string][]model.DependencyEdge) []apiv1.DependencyEdge {
+	output := []apiv1.DependencyEdge{}
+	followed := map[string]bool{root: true}
+	level := first
+	for d := 1; d <= depth && len(level) > 0; d++ {
+		var next []model.DependencyEdge
+		for _, e := range level {
+			output = append(output, apiv1.DependencyEdge{DependencyEdge: e, Depth: d})
+			if to := far(e); !followed[to] {
+				followed[to] = true
+				next = append(next, edges[to]...)
+			}
+		}
+		level = next
+	}
+	return output
+}
+
+// validateDependencies checks the dependencies of a version of the service being written name distinct existing
+// services other than itself with valid constraints, and that none of them depends on the service in turn.
+func (s *Service) validateDependencies(name string, dependencies []model.Dependency) error {
+	if len(dependencies) == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	names := make([]string, 0, len(dependencies))
+	for _, d := range dependencies {
+		if d.Name == "" || d.Name == name || seen[d.Name] {
+			return errors.New(customerrors.ErrInvalidDependency)
+		}
+		if d.Constraint != "" {
+			if _, err := semver.ParseConstraint(d.Constraint); err != nil {
+				return errors.New(customerrors.ErrInvalidDependency)
+			}
+		}
+		seen[d.Name] = true
+		names = append(names, d.Name)
+	}
+	summaries, err := s.repo.GetSummaries(names)
+	if err != nil {
+		return err
+	}
+	if len(summaries) != len(names) {
+		return errors.New(customerrors.ErrDependencyNotFound)
+	}
+	// the version is written in the same transaction, holding the lock until then keeps a concurrent
+	// writer from adding the way back at the same time.
+	edges, err := s.repo.LockDependencyEdges()
+	if err != nil {
+		return err
+	}
+	// the edges of every live version count, not only the current ones, so promoting or rolling back
+	// a version can not close a cycle either.
+	graph := map[string][]string{}
+	for _, e := range edges {
+		graph[e.Service] = append(graph[e.Service], e.Dependency)
+	}
+	visited := map[string]bool{}
+	pending := names
+	for len(pending) > 0 {
+		next := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if next == name {
+			return errors.New(customerrors.ErrDependencyCycle)
+		}
+		if visited[next] {
+			continue
+		}
+		visited[next] = true
+		pending = append(pending, graph[next]...)
+	}
+	return nil
+}
+
+// inherit gives a new version the owner and the dependencies of the current version of the service
+// when they are left out.
+func (s *Service) inherit(service *model.Service) error {
+	current, err := s.serviceDetails(service.Name)
+	if err != nil {
+		return err
+	}
+	if service.Owner == "" {
+		service.Owner = current.Owner
+	}
+	if service.Dependencies == nil {
+		version, err := s.repo.GetByNameAndVersion(service.Name, current.CurrentVersion)
+		if err != nil {
+			return err
+		}
+		service.Dependencies = []model.Dependency{}
+		for _, d := range version.Dependencies {
+			service.Dependencies = append(service.Dependencies, model.Dependency{Name: d.Name, Constraint: d.Constraint})
+		}
+	}
+	return nil
+}
+
+func parseDepth(depth string) (int, error) {
+	if depth == "" {
+		return 1, nil
+	}
+	levels, err := util.StringToInt(depth)
+	if err != nil || levels < 1 || levels > maxDependencyDepth {
+		return 0, errors.New(customerrors.ErrInvalidDepth)
+	}
+	return levels, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"testing"
+)
+
+func dependsOn(names ...string) []model.Dependency {
+	dependencies := []model.Dependency{}
+	for _, name := range names {
+		dependencies = append(dependencies, model.Dependency{Name: name})
+	}
+	return dependencies
+}
+
+// edges returns the edges of a traversal as service->dependency@depth.
+func edges(graph apiv1.DependencyGraph) []string {
+	var got []string
+	for _, e := range graph.Edges {
+		got = append(got, fmt.Sprintf("%s->%s@%d", e.Service, e.Dependency, e.Depth))
+	}
+	return got
+}
+
+func TestDependenciesShouldBeValidated(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "ledger"})
+
+	_, err := s.Create(&model.Service{Name: "payments", Dependencies: dependsOn("payments")})
+	assert.EqualError(t, err, customerrors.ErrInvalidDependency)
+
+	_, err = s.Create(&model.Service{Name: "payments", Dependencies: dependsOn("ledger", "ledger")})
+	assert.EqualError(t, err, customerrors.ErrInvalidDependency)
+
+	_, err = s.Create(&model.Service{Name: "payments", Dependencies: []model.Dependency{{Name: "ledger", Constraint: "^^1"}}})
+	assert.EqualError(t, err, customerrors.ErrInvalidDependency)
+
+	_, err = s.Create(&model.Service{Name: "payments", Dependencies: dependsOn("unknown")})
+	assert.EqualError(t, err, customerrors.ErrDependencyNotFound)
+
+	_, err = s.Create(&model.Service{Name: "payments", Dependencies: []model.Dependency{{Name: "ledger", Constraint: "^1.0"}}})
+	assert.NoError(t, err)
+}
+
+func TestDependenciesShouldNotCreateCycles(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "ledger"})
+	_, _ = s.Create(&model.Service{Name: "payments", Dependencies: dependsOn("ledger")})
+	_, _ = s.Create(&model.Service{Name: "checkout", Dependencies: dependsOn("payments")})
+
+	_, err := s.CreateVersion(&model.Service{Name: "ledger", Dependencies: dependsOn("checkout")})
+	assert.EqualError(t, err, customerrors.ErrDependencyCycle)
+
+	_, err = s.UpdateVersion(&model.Service{Name: "ledger", Version: "1.0.0", Dependencies: dependsOn("payments")})
+	assert.EqualError(t, err, customerrors.ErrDependencyCycle)
+
+	// a prerelease which is not current closes the cycle as well once promoted.
+	_, err = s.CreateVersion(&model.Service{Name: "payments", Version: "2.0.0-rc.1", Dependencies: dependsOn("checkout")})
+	assert.EqualError(t, err, customerrors.ErrDependencyCycle)
+}
+
+func TestCreateVersionShouldKeepDependencies(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "ledger"})
+	_, _ = s.Create(&model.Service{Name: "fraud"})
+	_, _ = s.Create(&model.Service{Name: "payments", Dependencies: dependsOn("ledger")})
+
+	_, err := s.CreateVersion(&model.Service{Name: "payments"})
+	assert.NoError(t, err)
+	graph, err := s.FetchDependencies("payments", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0", graph.Version)
+	assert.Equal(t, []string{"payments->ledger@1"}, edges(graph))
+
+	_, err = s.CreateVersion(&model.Service{Name: "payments", Dependencies: dependsOn("fraud")})
+	assert.NoError(t, err)
+	graph, _ = s.FetchDependencies("payments", "", "")
+	assert.Equal(t, []string{"payments->fraud@1"}, edges(graph))
+
+	graph, _ = s.FetchDependencies("payments", "1.0.0", "")
+	assert.Equal(t, []string{"payments->ledger@1"}, edges(graph))
+}
+
+func TestDependencyTraversalShouldFollowCurrentVersions(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "ledger"})
+	_, _ = s.Create(&model.Service{Name: "fraud", Dependencies: dependsOn("ledger")})
+	_, _ = s.Create(&model.Service{Name: "payments", Dependencies: dependsOn("ledger", "fraud")})
+	_, _ = s.Create(&model.Service{Name: "checkout", Dependencies: dependsOn("payments")})
+	// the prerelease is not current, its dependencies are not followed.
+	_, _ = s.Create(&model.Service{Name: "search"})
+	_, err := s.CreateVersion(&model.Service{Name: "fraud", Version: "2.0.0-rc.1", Dependencies: dependsOn("search")})
+	assert.NoError(t, err)
+
+	graph, err := s.FetchDependencies("checkout", "", "3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"checkout->payments@1", "payments->fraud@2", "payments->ledger@2", "fraud->ledger@3"}, edges(graph))
+
+	graph, err = s.FetchDependencies("checkout", "", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"checkout->payments@1"}, edges(graph))
+
+	graph, err = s.FetchDependents("ledger", "32")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fraud->ledger@1", "payments->ledger@1", "payments->fraud@2", "checkout->payments@2"}, edges(graph))
+
+	graph, err = s.FetchDependents("checkout", "")
+	assert.NoError(t, err)
+	assert.Empty(t, graph.Edges)
+
+	_, err = s.FetchDependents("ledger", "33")
+	assert.EqualError(t, err, customerrors.ErrInvalidDepth)
+
+	_, err = s.FetchDependencies("unknown", "", "")
+	assert.EqualError(t, err, customerrors.ErrServiceNotFound)
+}
+
+func TestFetchGraphShouldDescribeNodes(t *testing.T) {
+	s := newTestService()
+	_, _ = s.CreateTeam(&model.Team{Name: "payments-platform"})
+	_, _ = s.Create(&model.Service{Name: "ledger"})
+	_, _ = s.Create(&model.Service{Name: "search"})
+	_, _ = s.Create(&model.Service{Name: "payments", Owner: "payments-platform", Dependencies: dependsOn("ledger")})
+	_, _ = s.Create(&model.Service{Name: "checkout", Dependencies: dependsOn("payments")})
+
+	graph, err := s.FetchGraph("", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []apiv1.GraphNode{
+		{Name: "checkout", Version: "1.0.0"},
+		{Name: "ledger", Version: "1.0.0"},
+		{Name: "payments", Version: "1.0.0", Owner: "payments-platform"},
+	}, graph.Nodes)
+	assert.Len(t, graph.Edges, 2)
+
+	graph, err = s.FetchGraph("ledger", "dependents", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []apiv1.GraphNode{{Name: "ledger", Version: "1.0.0"}, {Name: "payments", Version: "1.0.0", Owner: "payments-platform"}}, graph.Nodes)
+
+	graph, err = s.FetchGraph("search", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []apiv1.GraphNode{{Name: "search", Version: "1.0.0"}}, graph.Nodes)
+	assert.Empty(t, graph.Edges)
+
+	_, err = s.FetchGraph("ledger", "upstream", "")
+	assert.EqualError(t, err, customerrors.ErrInvalidGraphDirection)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"github.com/suyog1pathak/services/pkg/util"
+	"math"
+	"time"
+)
+
+const (
+	// defaultDeprecationDays is how far ahead the deprecation report looks unless told otherwise.
+	defaultDeprecationDays = 30
+	maxDeprecationDays     = 3650
+)
+
+// FetchDeprecations reports the deprecated versions sunsetting within days, 30 by default, and the ones already
+// past their sunset date.
+func (s *Service) FetchDeprecations(days string) (apiv1.DeprecationReport, error) {
+	within, err := parseDays(days)
+	if err != nil {
+		return apiv1.DeprecationReport{}, err
+	}
+	now := time.Now()
+	report := apiv1.DeprecationReport{
+		Days:         within,
+		Until:        now.AddDate(0, 0, within),
+		Deprecations: []apiv1.Deprecation{},
+	}
+	versions, err := s.repo.GetDeprecations(report.Until)
+	if err != nil {
+		return apiv1.DeprecationReport{}, err
+	}
+	for _, v := range versions {
+		report.Deprecations = append(report.Deprecations, apiv1.Deprecation{
+			Service:      v.Name,
+			Version:      v.Version,
+			Owner:        string(v.Owner),
+			DeprecatedAt: v.DeprecatedAt,
+			SunsetDate:   *v.SunsetDate,
+			Successor:    v.Successor,
+			DaysLeft:     int(math.Floor(v.SunsetDate.Sub(now).Hours() / 24)),
+		})
+	}
+	return report, nil
+}
+
+// FetchCurrent returns the version of the service served to clients, its current version or the latest one
+// when it is not set.
+func (s *Service) FetchCurrent(name string) (model.Service, error) {
+	details, err := s.serviceDetails(name)
+	if err != nil {
+		return model.Service{}, err
+	}
+	return *details.Service, nil
+}
+
+func parseDays(days string) (int, error) {
+	if days == "" {
+		return defaultDeprecationDays, nil
+	}
+	n, err := util.StringToInt(days)
+	if err != nil || n < 0 || n > maxDeprecationDays {
+		return 0, errors.New(customerrors.ErrInvalidDays)
+	}
+	return n, nil
+}
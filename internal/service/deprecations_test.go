@@ -0,0 +1,74 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestDeprecationShouldRecordSuccessor(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+	_, _ = s.Create(&model.Service{Name: "payments-v2"})
+
+	_, err := s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Successor: "payments-v2"})
+	assert.EqualError(t, err, customerrors.ErrInvalidSuccessor)
+	_, err = s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Lifecycle: model.LifecycleDeprecated, Successor: "payments-v3"})
+	assert.EqualError(t, err, customerrors.ErrInvalidSuccessor)
+
+	_, err = s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Lifecycle: model.LifecycleDeprecated, Successor: "payments-v2"})
+	assert.NoError(t, err)
+	current, err := s.FetchCurrent("payments")
+	assert.NoError(t, err)
+	assert.Equal(t, "payments-v2", current.Successor)
+	assert.NotNil(t, current.DeprecatedAt)
+	deprecatedAt := *current.DeprecatedAt
+
+	// the deprecation time is kept through retirement and cleared back in production.
+	_, _ = s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", SunsetDate: &deprecatedAt})
+	current, _ = s.FetchCurrent("payments")
+	assert.Equal(t, deprecatedAt, *current.DeprecatedAt)
+	_, err = s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Lifecycle: model.LifecycleProduction})
+	assert.NoError(t, err)
+	current, _ = s.FetchCurrent("payments")
+	assert.Nil(t, current.DeprecatedAt)
+	assert.Nil(t, current.SunsetDate)
+	assert.Empty(t, current.Successor)
+}
+
+func TestFetchDeprecationsShouldReportSunsetsWithinDays(t *testing.T) {
+	s := newTestService()
+	deprecate := func(name string, sunset time.Time) {
+		_, _ = s.Create(&model.Service{Name: name})
+		_, err := s.UpdateVersion(&model.Service{Name: name, Version: "1.0.0", Lifecycle: model.LifecycleDeprecated, SunsetDate: &sunset})
+		assert.NoError(t, err)
+	}
+	now := time.Now()
+	deprecate("ledger", now.AddDate(0, 0, 20).Add(time.Hour))
+	deprecate("payments", now.AddDate(0, 0, 5).Add(time.Hour))
+	deprecate("search", now.AddDate(0, 0, 90))
+	// past its sunset date but not retired yet.
+	deprecate("fraud", now.AddDate(0, 0, -2).Add(time.Hour))
+	_, _ = s.Create(&model.Service{Name: "checkout"})
+
+	report, err := s.FetchDeprecations("")
+	assert.NoError(t, err)
+	assert.Equal(t, 30, report.Days)
+	var got []string
+	var left []int
+	for _, d := range report.Deprecations {
+		got = append(got, d.Service)
+		left = append(left, d.DaysLeft)
+	}
+	assert.Equal(t, []string{"fraud", "payments", "ledger"}, got)
+	assert.Equal(t, []int{-2, 5, 20}, left)
+
+	report, err = s.FetchDeprecations("7")
+	assert.NoError(t, err)
+	assert.Len(t, report.Deprecations, 2)
+
+	_, err = s.FetchDeprecations("-1")
+	assert.EqualError(t, err, customerrors.ErrInvalidDays)
+}
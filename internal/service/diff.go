@@ -0,0 +1,134 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	"github.com/suyog1pathak/services/pkg/jsonpatch"
+	"github.com/suyog1pathak/services/pkg/model"
+	"sort"
+	"strings"
+)
+
+// diffIgnored are the members of a version which identify or track the row rather than describe the service.
+var diffIgnored = []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt", "deprecatedAt", "serviceName", "version", "revision", "bump"}
+
+// FetchDiff returns what changed between two versions of the service, every member of the JSON of a version
+// is compared so fields added to the model are picked up without changes here.
+func (s *Service) FetchDiff(name, from, to string) (apiv1.VersionDiff, error) {
+	a, err := s.FetchByVersionAndName(name, from)
+	if err != nil {
+		return apiv1.VersionDiff{}, err
+	}
+	b, err := s.FetchByVersionAndName(name, to)
+	if err != nil {
+		return apiv1.VersionDiff{}, err
+	}
+	fieldsA, err := diffFields(a)
+	if err != nil {
+		return apiv1.VersionDiff{}, err
+	}
+	fieldsB, err := diffFields(b)
+	if err != nil {
+		return apiv1.VersionDiff{}, err
+	}
+	patch, err := jsonpatch.Diff(fieldsA, fieldsB)
+	if err != nil {
+		return apiv1.VersionDiff{}, err
+	}
+	return apiv1.VersionDiff{
+		Service: name,
+		From:    a.Version,
+		To:      b.Version,
+		Patch:   patch,
+		Unified: unified(name+"@"+a.Version, name+"@"+b.Version, fieldsA, fieldsB),
+	}, nil
+}
+
+// diffFields returns the members of the JSON of the version which are compared.
+func diffFields(s model.Service) (map[string]interface{}, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, member := range diffIgnored {
+		delete(fields, member)
+	}
+	return fields, nil
+}
+
+// unified renders the fields of both versions one per line as path: value, in the unified diff format with a
+// single hunk over the whole rendering. Nothing is rendered when the versions do not differ.
+func unified(fromName, toName string, a, b map[string]interface{}) string {
+	linesA, linesB := map[string]string{}, map[string]string{}
+	flatten("", a, linesA)
+	flatten("", b, linesB)
+	var hunk []string
+	changed := false
+	for _, path := range sortedKeys(linesA, linesB) {
+		lineA, inA := linesA[path]
+		lineB, inB := linesB[path]
+		if inA && inB && lineA == lineB {
+			hunk = append(hunk, " "+lineA)
+			continue
+		}
+		changed = true
+		if inA {
+			hunk = append(hunk, "-"+lineA)
+		}
+		if inB {
+			hunk = append(hunk, "+"+lineB)
+		}
+	}
+	if !changed {
+		return ""
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n@@ -1,%d +1,%d @@\n", fromName, toName, len(linesA), len(linesB))
+	for _, line := range hunk {
+		out.WriteString(line + "\n")
+	}
+	return out.String()
+}
+
+// flatten adds a path: value line per scalar of v, keyed by its path. Members are joined with dots and array
+// elements indexed, empty objects and arrays get a line of their own so clearing them shows.
+func flatten(path string, v interface{}, lines map[string]string) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if len(value) > 0 {
+			for key, member := range value {
+				flatten(strings.TrimPrefix(path+"."+key, "."), member, lines)
+			}
+			return
+		}
+	case []interface{}:
+		if len(value) > 0 {
+			for i, element := range value {
+				flatten(fmt.Sprintf("%s[%d]", path, i), element, lines)
+			}
+			return
+		}
+	}
+	data, _ := json.Marshal(v)
+	lines[path] = path + ": " + string(data)
+}
+
+func sortedKeys(maps ...map[string]string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/jsonpatch"
+	"github.com/suyog1pathak/services/pkg/model"
+	"testing"
+)
+
+func TestFetchDiffShouldCompareFields(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments", Description: "payments",
+		Labels: model.NewLabels(map[string]string{"tier": "1", "pci": ""})})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments", Description: "payments api", Lifecycle: model.LifecycleDeprecated,
+		Labels: model.NewLabels(map[string]string{"tier": "2"})})
+
+	diff, err := s.FetchDiff("payments", "1", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", diff.From)
+	assert.Equal(t, "2.0.0", diff.To)
+	assert.Equal(t, []jsonpatch.Operation{
+		{Op: jsonpatch.OpReplace, Path: "/describe", Value: "payments api"},
+		{Op: jsonpatch.OpReplace, Path: "/isActive", Value: false},
+		{Op: jsonpatch.OpRemove, Path: "/labels/pci"},
+		{Op: jsonpatch.OpReplace, Path: "/labels/tier", Value: "2"},
+		{Op: jsonpatch.OpReplace, Path: "/lifecycle", Value: "deprecated"},
+		{Op: jsonpatch.OpReplace, Path: "/tags", Value: "tier=2"},
+	}, diff.Patch)
+	assert.Equal(t, `--- payments@1.0.0
++++ payments@2.0.0
+@@ -1,7 +1,6 @@
+-describe: "payments"
++describe: "payments api"
+-isActive: true
++isActive: false
+-labels.pci: ""
+-labels.tier: "1"
++labels.tier: "2"
+-lifecycle: "production"
++lifecycle: "deprecated"
+ owner: ""
+-tags: "pci,tier=1"
++tags: "tier=2"
+`, diff.Unified)
+
+	diff, err = s.FetchDiff("payments", "2.0.0", "2.0.0")
+	assert.NoError(t, err)
+	assert.Empty(t, diff.Patch)
+	assert.Empty(t, diff.Unified)
+
+	_, err = s.FetchDiff("payments", "", "2.0.0")
+	assert.EqualError(t, err, customerrors.ErrInvalidVersion)
+	_, err = s.FetchDiff("payments", "1.0.0", "3.0.0")
+	assert.EqualError(t, err, customerrors.ErrServiceWithVersionNotFound)
+}
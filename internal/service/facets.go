@@ -0,0 +1,49 @@
+package service
+
+import (
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	"github.com/suyog1pathak/services/pkg/model"
+	"sort"
+)
+
+// FetchFacets counts the services matching the search per label, active state and owner, the values of
+// each facet are ordered by count and then by value.
+func (s *Service) FetchFacets(search model.ServiceSearch) (apiv1.Facets, error) {
+	counts, err := s.repo.GetFacetCounts(search)
+	if err != nil {
+		return apiv1.Facets{}, err
+	}
+	facets := apiv1.Facets{
+		Labels:   map[string][]apiv1.FacetValue{},
+		IsActive: []apiv1.FacetValue{},
+		Owner:    []apiv1.FacetValue{},
+	}
+	for _, c := range counts {
+		value := apiv1.FacetValue{Value: c.Value, Count: c.Count}
+		switch c.Facet {
+		case model.FacetLabel:
+			facets.Labels[c.Key] = append(facets.Labels[c.Key], value)
+		case model.FacetActive:
+			facets.IsActive = append(facets.IsActive, value)
+		case model.FacetOwner:
+			facets.Owner = append(facets.Owner, value)
+		case model.FacetTotal:
+			facets.Total = c.Count
+		}
+	}
+	for _, values := range facets.Labels {
+		sortFacet(values)
+	}
+	sortFacet(facets.IsActive)
+	sortFacet(facets.Owner)
+	return facets, nil
+}
+
+func sortFacet(values []apiv1.FacetValue) {
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	"github.com/suyog1pathak/services/pkg/model"
+	"testing"
+)
+
+func TestFetchFacetsShouldCountServicesPerValue(t *testing.T) {
+	s := newTestService()
+	_, _ = s.CreateTeam(&model.Team{Name: "payments-platform"})
+	_, _ = s.Create(&model.Service{Name: "ledger", Labels: model.NewLabels(map[string]string{"tier": "1", "pci": ""})})
+	_, _ = s.Create(&model.Service{Name: "payments", Owner: "payments-platform", Lifecycle: model.LifecycleDeprecated, Labels: model.NewLabels(map[string]string{"tier": "1"})})
+	_, _ = s.Create(&model.Service{Name: "search", Labels: model.NewLabels(map[string]string{"tier": "2"})})
+	// both versions of payments are tier 1, the service is counted once.
+	_, _ = s.CreateVersion(&model.Service{Name: "payments", Labels: model.NewLabels(map[string]string{"tier": "1"})})
+
+	facets, err := s.FetchFacets(model.ServiceSearch{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), facets.Total)
+	assert.Equal(t, map[string][]apiv1.FacetValue{
+		"tier": {{Value: "1", Count: 2}, {Value: "2", Count: 1}},
+		"pci":  {{Value: "", Count: 1}},
+	}, facets.Labels)
+	assert.Equal(t, []apiv1.FacetValue{{Value: "true", Count: 3}, {Value: "false", Count: 1}}, facets.IsActive)
+	assert.Equal(t, []apiv1.FacetValue{{Value: "", Count: 2}, {Value: "payments-platform", Count: 1}}, facets.Owner)
+
+	selector, _ := model.ParseLabelSelector("tier=1")
+	facets, err = s.FetchFacets(model.ServiceSearch{Labels: selector, Owners: []string{"payments-platform"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), facets.Total)
+	assert.Equal(t, map[string][]apiv1.FacetValue{"tier": {{Value: "1", Count: 1}}}, facets.Labels)
+
+	facets, err = s.FetchFacets(model.ServiceSearch{Query: "unknown", Fields: []string{model.FieldName}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), facets.Total)
+	assert.Empty(t, facets.Labels)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"slices"
+	"time"
+)
+
+// lifecycleTransitions are the states a version can move to from each state, a version can always stay where it is.
+// Production versions are deprecated before they are retired and nothing comes back from retirement.
+var lifecycleTransitions = map[string][]string{
+	model.LifecycleExperimental: {model.LifecycleProduction, model.LifecycleDeprecated, model.LifecycleRetired},
+	model.LifecycleProduction:   {model.LifecycleDeprecated},
+	model.LifecycleDeprecated:   {model.LifecycleProduction, model.LifecycleRetired},
+	model.LifecycleRetired:      {},
+}
+
+// initLifecycle sets the lifecycle of a new version, experimental for a prerelease and production otherwise
+// unless one is given.
+func initLifecycle(service *model.Service, prerelease bool) error {
+	if service.Lifecycle == "" {
+		service.Lifecycle = model.LifecycleProduction
+		if prerelease {
+			service.Lifecycle = model.LifecycleExperimental
+		}
+	}
+	service.DeprecatedAt = nil
+	if !model.IsActiveLifecycle(service.Lifecycle) {
+		now := time.Now()
+		service.DeprecatedAt = &now
+	}
+	return validateLifecycle(service)
+}
+
+// transitionLifecycle checks the update moves the existing version along the lifecycle. Without a lifecycle the
+// version stays where it is, old clients setting isActive bring an inactive version back into production.
+func transitionLifecycle(existing model.Service, update *model.Service) error {
+	update.DeprecatedAt = nil
+	if update.Lifecycle == "" {
+		switch {
+		case update.IsActive && !existing.IsActive:
+			update.Lifecycle = model.LifecycleProduction
+		case update.SunsetDate != nil || update.Successor != "":
+			update.Lifecycle = existing.Lifecycle
+		default:
+			update.IsActive = false
+			return nil
+		}
+	}
+	if update.Lifecycle != existing.Lifecycle && model.IsLifecycle(update.Lifecycle) &&
+		!slices.Contains(lifecycleTransitions[existing.Lifecycle], update.Lifecycle) {
+		return errors.New(customerrors.ErrLifecycleTransition)
+	}
+	if !model.IsActiveLifecycle(update.Lifecycle) && existing.DeprecatedAt == nil {
+		now := time.Now()
+		update.DeprecatedAt = &now
+	}
+	return validateLifecycle(update)
+}
+
+// validateLifecycle checks the lifecycle, sunset date and successor of the version and derives isActive from the lifecycle.
+func validateLifecycle(service *model.Service) error {
+	if !model.IsLifecycle(service.Lifecycle) {
+		return errors.New(customerrors.ErrInvalidLifecycle)
+	}
+	if service.SunsetDate != nil && service.Lifecycle != model.LifecycleDeprecated {
+		return errors.New(customerrors.ErrInvalidSunsetDate)
+	}
+	if service.Successor != "" && service.Lifecycle != model.LifecycleDeprecated {
+		return errors.New(customerrors.ErrInvalidSuccessor)
+	}
+	service.IsActive = model.IsActiveLifecycle(service.Lifecycle)
+	return nil
+}
+
+// validateSuccessor checks the successor of a deprecated version is another existing service.
+func (s *Service) validateSuccessor(name, successor string) error {
+	if successor == "" {
+		return nil
+	}
+	if successor == name {
+		return errors.New(customerrors.ErrInvalidSuccessor)
+	}
+	count, err := s.repo.GetByNameCount(successor)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New(customerrors.ErrInvalidSuccessor)
+	}
+	return nil
+}
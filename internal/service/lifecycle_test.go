@@ -0,0 +1,84 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestNewVersionsShouldDefaultTheLifecycle(t *testing.T) {
+	s := newTestService()
+	created, err := s.Create(&model.Service{Name: "payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.LifecycleProduction, created.Lifecycle)
+	assert.True(t, created.IsActive)
+
+	created, err = s.CreateVersion(&model.Service{Name: "payments", Version: "2.0.0-rc.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.LifecycleExperimental, created.Lifecycle)
+	assert.True(t, created.IsActive)
+
+	created, err = s.CreateVersion(&model.Service{Name: "payments", Lifecycle: model.LifecycleDeprecated})
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0", created.Version)
+	assert.False(t, created.IsActive)
+
+	_, err = s.CreateVersion(&model.Service{Name: "payments", Lifecycle: "stable"})
+	assert.EqualError(t, err, customerrors.ErrInvalidLifecycle)
+	sunset := time.Now().Add(24 * time.Hour)
+	_, err = s.Create(&model.Service{Name: "ledger", SunsetDate: &sunset})
+	assert.EqualError(t, err, customerrors.ErrInvalidSunsetDate)
+}
+
+func TestUpdateVersionShouldEnforceLifecycleTransitions(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+	update := func(lifecycle string, sunset *time.Time) (model.Service, error) {
+		_, err := s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Lifecycle: lifecycle, SunsetDate: sunset})
+		v, _ := s.FetchByVersionAndName("payments", "1.0.0")
+		return v, err
+	}
+
+	_, err := update(model.LifecycleExperimental, nil)
+	assert.EqualError(t, err, customerrors.ErrLifecycleTransition)
+	_, err = update(model.LifecycleRetired, nil)
+	assert.EqualError(t, err, customerrors.ErrLifecycleTransition)
+
+	sunset := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+	v, err := update(model.LifecycleDeprecated, &sunset)
+	assert.NoError(t, err)
+	assert.Equal(t, model.LifecycleDeprecated, v.Lifecycle)
+	assert.False(t, v.IsActive)
+	assert.Equal(t, sunset, *v.SunsetDate)
+
+	// back into production, the sunset date no longer applies.
+	v, err = update(model.LifecycleProduction, nil)
+	assert.NoError(t, err)
+	assert.True(t, v.IsActive)
+	assert.Nil(t, v.SunsetDate)
+	_, err = update("", &sunset)
+	assert.EqualError(t, err, customerrors.ErrInvalidSunsetDate)
+
+	_, _ = update(model.LifecycleDeprecated, nil)
+	v, err = update(model.LifecycleRetired, nil)
+	assert.NoError(t, err)
+	assert.False(t, v.IsActive)
+	_, err = update(model.LifecycleProduction, nil)
+	assert.EqualError(t, err, customerrors.ErrLifecycleTransition)
+	_, err = s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", IsActive: true})
+	assert.EqualError(t, err, customerrors.ErrLifecycleTransition)
+}
+
+func TestSearchAndSortShouldFilterByLifecycle(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "ledger"})
+	_, _ = s.Create(&model.Service{Name: "payments", Lifecycle: model.LifecycleDeprecated})
+	_, _ = s.Create(&model.Service{Name: "search", Version: "1.0.0-beta.1"})
+
+	search := model.ServiceSearch{Lifecycles: []string{model.LifecycleDeprecated, model.LifecycleExperimental}}
+	page, err := s.SearchAndSort(search, model.ServiceSort{{Key: model.SortName}}, 1, 10, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"payments", "search"}, names(page.Data))
+}
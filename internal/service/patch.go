@@ -0,0 +1,119 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/jsonpatch"
+	"github.com/suyog1pathak/services/pkg/model"
+	"reflect"
+)
+
+// PatchVersion updates an existing version with a JSON Merge Patch or a JSON Patch document given by its media type,
+// the members the patch changes are written even when they are set to false, empty or null. Read only members are
+// ignored as they are in UpdateVersion, tags are rejected unless they match the labels, isActive moves the version to production or deprecates it unless the lifecycle
+// is patched as well. It fails with ErrRevisionMismatch when the version is not at one of the revisions, any matches when nil.
+func (s *Service) PatchVersion(name, version, mediaType string, patch []byte, revisions []int) (*model.Service, error) {
+	var after model.Service
+	err := s.transaction(func(tx *Service) error {
+		existing, err := tx.FetchByVersionAndName(name, version)
+		if err != nil {
+			return err
+		}
+		update, fields, err := patchVersion(existing, mediaType, patch)
+		if err != nil {
+			return err
+		}
+		before, err := tx.updateVersion(update, revisions, fields...)
+		if err != nil {
+			return err
+		}
+		if after, err = tx.repo.GetByNameAndVersion(update.Name, update.Version); err != nil {
+			return err
+		}
+		return tx.audit(model.AuditUpdateVersion, update.Name, update.Version, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// patchVersion applies the patch to the JSON of the existing version and returns the update of the members it
+// changed, with the fields to write even when they are zero.
+func patchVersion(existing model.Service, mediaType string, patch []byte) (*model.Service, []string, error) {
+	doc, err := json.Marshal(existing)
+	if err != nil {
+		return nil, nil, err
+	}
+	var patched []byte
+	switch mediaType {
+	case jsonpatch.MergePatchMediaType:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	case jsonpatch.MediaType:
+		var ops []jsonpatch.Operation
+		if err = json.Unmarshal(patch, &ops); err == nil {
+			patched, err = jsonpatch.Apply(doc, ops)
+		}
+	default:
+		return nil, nil, errors.New(customerrors.ErrUnsupportedMediaType)
+	}
+	if errors.Is(err, jsonpatch.ErrConflict) {
+		return nil, nil, errors.New(customerrors.ErrPatchConflict)
+	}
+	var from, to map[string]interface{}
+	var p model.Service
+	if err != nil || json.Unmarshal(doc, &from) != nil || json.Unmarshal(patched, &to) != nil || json.Unmarshal(patched, &p) != nil {
+		return nil, nil, errors.New(customerrors.ErrInvalidPatch)
+	}
+
+	update := &model.Service{Name: existing.Name, Version: existing.Version}
+	var fields []string
+	for _, member := range jsonpatch.Keys(from, to) {
+		if reflect.DeepEqual(from[member], to[member]) {
+			continue
+		}
+		switch member {
+		case "describe":
+			update.Description = p.Description
+			fields = append(fields, "Description")
+		case "lifecycle":
+			// an empty lifecycle is rejected rather than read as unchanged.
+			if update.Lifecycle = p.Lifecycle; p.Lifecycle == "" {
+				return nil, nil, errors.New(customerrors.ErrInvalidLifecycle)
+			}
+		case "isActive":
+			if reflect.DeepEqual(from["lifecycle"], to["lifecycle"]) {
+				if update.IsActive = p.IsActive; !p.IsActive {
+					update.Lifecycle = model.LifecycleDeprecated
+				}
+			}
+		case "sunsetDate":
+			update.SunsetDate = p.SunsetDate
+			fields = append(fields, "SunsetDate")
+		case "successor":
+			update.Successor = p.Successor
+			fields = append(fields, "Successor")
+		case "owner":
+			update.Owner = p.Owner
+			fields = append(fields, "Owner")
+		case "changelog":
+			update.Changelog = p.Changelog
+			fields = append(fields, "Changelog")
+		case "tags":
+			// tags are read only, a patch is rejected unless it writes the tags of the labels it leaves.
+			update.Tags = p.Tags
+		case "labels":
+			update.Labels = p.Labels
+			if update.Labels == nil {
+				update.Labels = model.Labels{}
+			}
+		case "dependencies":
+			update.Dependencies = p.Dependencies
+			if update.Dependencies == nil {
+				update.Dependencies = []model.Dependency{}
+			}
+		}
+	}
+	return update, fields, nil
+}
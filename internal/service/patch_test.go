@@ -0,0 +1,73 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/jsonpatch"
+	"github.com/suyog1pathak/services/pkg/model"
+	"testing"
+)
+
+func TestPatchVersionShouldWriteExactlyTheMembersSent(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "ledger"})
+	_, _ = s.Create(&model.Service{
+		Name:         "payments",
+		Description:  "Payments",
+		Changelog:    "First release.",
+		Labels:       model.Labels{{Key: "tier", Value: "1"}, {Key: "pci", Value: "yes"}},
+		Dependencies: []model.Dependency{{Name: "ledger"}},
+	})
+
+	patched, err := s.PatchVersion("payments", "1.0.0", jsonpatch.MergePatchMediaType,
+		[]byte(`{"isActive": false, "describe": "", "changelog": null, "labels": {"pci": null}, "version": "9.9.9"}`), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, patched.Revision)
+	version, _ := s.FetchByVersionAndName("payments", "1.0.0")
+	assert.False(t, version.IsActive)
+	assert.Equal(t, model.LifecycleDeprecated, version.Lifecycle)
+	assert.Empty(t, version.Description)
+	assert.Empty(t, version.Changelog)
+	assert.Equal(t, "tier=1", version.Tags)
+	assert.Len(t, version.Dependencies, 1)
+
+	_, err = s.PatchVersion("payments", "1.0.0", jsonpatch.MediaType, []byte(`[
+		{"op": "test", "path": "/lifecycle", "value": "deprecated"},
+		{"op": "replace", "path": "/isActive", "value": true},
+		{"op": "remove", "path": "/dependencies"}
+	]`), []int{2})
+	assert.NoError(t, err)
+	version, _ = s.FetchByVersionAndName("payments", "1.0.0")
+	assert.True(t, version.IsActive)
+	assert.Equal(t, model.LifecycleProduction, version.Lifecycle)
+	assert.Empty(t, version.Dependencies)
+	assert.Equal(t, 3, version.Revision)
+
+	_, err = s.PatchVersion("payments", "1.0.0", jsonpatch.MediaType, []byte(`[{"op": "test", "path": "/isActive", "value": false}]`), nil)
+	assert.EqualError(t, err, customerrors.ErrPatchConflict)
+	_, err = s.PatchVersion("payments", "1.0.0", jsonpatch.MergePatchMediaType, []byte(`{"describe": 1}`), nil)
+	assert.EqualError(t, err, customerrors.ErrInvalidPatch)
+	_, err = s.PatchVersion("payments", "1.0.0", jsonpatch.MergePatchMediaType, []byte(`{"lifecycle": null}`), nil)
+	assert.EqualError(t, err, customerrors.ErrInvalidLifecycle)
+	_, err = s.PatchVersion("payments", "1.0.0", jsonpatch.MergePatchMediaType, []byte(`{"describe": "x"}`), []int{2})
+	assert.EqualError(t, err, customerrors.ErrRevisionMismatch)
+
+	events, _ := s.repo.GetAuditEvents(model.AuditSearch{Actions: []string{model.AuditUpdateVersion}, Limit: 10})
+	assert.Len(t, events, 2)
+}
+
+func TestPatchVersionShouldRejectWrittenTags(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments", Labels: model.Labels{{Key: "tier", Value: "1"}}})
+
+	_, err := s.PatchVersion("payments", "1.0.0", jsonpatch.MediaType, []byte(`[{"op": "replace", "path": "/tags", "value": "tier=2"}]`), nil)
+	assert.EqualError(t, err, customerrors.ErrReadOnlyTags)
+	_, err = s.PatchVersion("payments", "1.0.0", jsonpatch.MergePatchMediaType, []byte(`{"labels": {"tier": "2"}, "tags": "tier=3"}`), nil)
+	assert.EqualError(t, err, customerrors.ErrReadOnlyTags)
+
+	// tags matching the labels patched along are accepted.
+	_, err = s.PatchVersion("payments", "1.0.0", jsonpatch.MergePatchMediaType, []byte(`{"labels": {"tier": "2"}, "tags": "tier=2"}`), nil)
+	assert.NoError(t, err)
+	version, _ := s.FetchByVersionAndName("payments", "1.0.0")
+	assert.Equal(t, "tier=2", version.Tags)
+}
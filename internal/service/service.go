@@ -3,24 +3,100 @@ package service
 import (
 	"errors"
 	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	"github.com/suyog1pathak/services/pkg/cursor"
 	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
 	"github.com/suyog1pathak/services/pkg/model"
+	"github.com/suyog1pathak/services/pkg/semver"
 	"math"
+	"slices"
+	"time"
 )
 
-func Create(service *model.Service) (apiv1.Service, error) {
+// firstVersion is the version of a newly created service unless one is provided.
+var firstVersion = semver.Version{Major: 1}
+
+// Service implements the catalog business rules on top of a model.ServiceRepository.
+type Service struct {
+	repo    model.ServiceRepository
+	teams   model.TeamRepository
+	cursors *cursor.Codec
+	// caller is who the changes are recorded as made by in the audit log.
+	caller Caller
+}
+
+func NewService(repo model.ServiceRepository, teams model.TeamRepository, cursors *cursor.Codec) *Service {
+	return &Service{repo: repo, teams: teams, cursors: cursors}
+}
+
+// pageCursor is the position a next or prev token of the listing points at, it is only valid for the sort it was created for.
+type pageCursor struct {
+	Sort     string             `json:"sort"`
+	Before   bool               `json:"before,omitempty"`
+	Position model.ServiceCount `json:"position"`
+}
+
+// Create creates a service with its first version, 1.0.0 unless one is given.
+func (s *Service) Create(service *model.Service) (apiv1.Service, error) {
+	var response apiv1.Service
+	err := s.transaction(func(tx *Service) (err error) {
+		if response, err = tx.create(service); err != nil {
+			return err
+		}
+		return tx.audit(model.AuditCreate, service.Name, service.Version, nil, service)
+	})
+	return response, err
+}
+
+func (s *Service) create(service *model.Service) (apiv1.Service, error) {
 	var response apiv1.Service
-	service.Version = 1
-	_, err := FetchByName(service.Name)
+	version := firstVersion
+	if service.Version != "" {
+		v, err := parseVersion(service.Version)
+		if err != nil {
+			return apiv1.Service{}, err
+		}
+		version = v
+	}
+	service.SetVersion(version)
+	err := deriveTags(service, nil)
+	if err != nil {
+		return apiv1.Service{}, err
+	}
+	err = initLifecycle(service, version.Prerelease != "")
+	if err != nil {
+		return apiv1.Service{}, err
+	}
+	err = s.validateOwner(service.Owner)
+	if err != nil {
+		return apiv1.Service{}, err
+	}
+	err = s.validateDependencies(service.Name, service.Dependencies)
+	if err != nil {
+		return apiv1.Service{}, err
+	}
+	err = s.validateSuccessor(service.Name, service.Successor)
+	if err != nil {
+		return apiv1.Service{}, err
+	}
+	_, err = s.FetchByName(service.Name)
 	if err != nil {
 		if err.Error() == customerrors.ErrServiceNotFound {
-			err = service.Add()
+			err = s.repo.Add(service)
+			if err != nil {
+				// a concurrent create won the race for the first version.
+				if err.Error() == customerrors.ErrServiceVersionExists {
+					return apiv1.Service{}, errors.New(customerrors.ErrServiceFoundWithSameName)
+				}
+				return apiv1.Service{}, err
+			}
+			// a re-created service starts over, whatever the deleted one pointed at is replaced.
+			err = s.repo.SetCurrentVersion(model.NewCurrentVersion(service.Name, version))
 			if err != nil {
 				return apiv1.Service{}, err
 			}
 			response = apiv1.Service{
 				TotalVersions:  1,
-				CurrentVersion: 1,
+				CurrentVersion: service.Version,
 				Service:        service,
 			}
 			return response, nil
@@ -30,102 +106,499 @@ func Create(service *model.Service) (apiv1.Service, error) {
 	return response, errors.New(customerrors.ErrServiceFoundWithSameName)
 }
 
-func CreateVersion(service *model.Service) (apiv1.Service, error) {
+// CreateVersion publishes a new version of an existing service. The version is either given explicitly
+// or derived from the latest version by bumping its major (the default), minor or patch part.
+// A release higher than the current version becomes current, prereleases and backports have to be promoted.
+// The owner and dependencies of the current version are kept unless they are given.
+func (s *Service) CreateVersion(service *model.Service) (apiv1.Service, error) {
 	var response apiv1.Service
-	oldVersions, err := FetchByName(service.Name)
-	if err != nil {
-		if err.Error() == customerrors.ErrServiceNotFound {
-			return apiv1.Service{}, err
+	err := s.transaction(func(tx *Service) (err error) {
+		if response, err = tx.createVersion(service); err != nil {
+			return err
 		}
+		return tx.audit(model.AuditCreateVersion, service.Name, service.Version, nil, service)
+	})
+	return response, err
+}
+
+func (s *Service) createVersion(service *model.Service) (apiv1.Service, error) {
+	var response apiv1.Service
+	if err := deriveTags(service, nil); err != nil {
+		return apiv1.Service{}, err
+	}
+	if err := s.validateOwner(service.Owner); err != nil {
+		return apiv1.Service{}, err
+	}
+	if err := s.validateDependencies(service.Name, service.Dependencies); err != nil {
+		return apiv1.Service{}, err
+	}
+	if err := s.validateSuccessor(service.Name, service.Successor); err != nil {
 		return apiv1.Service{}, err
 	}
-	service.Version = oldVersions[len(oldVersions)-1].Version + 1
-	err = service.Add()
+	if service.Owner == "" || service.Dependencies == nil {
+		if err := s.inherit(service); err != nil {
+			return apiv1.Service{}, err
+		}
+	}
+	if service.Version != "" {
+		if service.Bump != "" {
+			return apiv1.Service{}, errors.New(customerrors.ErrInvalidVersionBump)
+		}
+		version, err := parseVersion(service.Version)
+		if err != nil {
+			return apiv1.Service{}, err
+		}
+		if err = initLifecycle(service, version.Prerelease != ""); err != nil {
+			return apiv1.Service{}, err
+		}
+		_, err = s.FetchByName(service.Name)
+		if err != nil {
+			return apiv1.Service{}, err
+		}
+		service.SetVersion(version)
+		err = s.repo.Add(service)
+		if err != nil {
+			return apiv1.Service{}, err
+		}
+	} else {
+		bump := service.Bump
+		if bump == "" {
+			bump = semver.Major
+		}
+		if bump != semver.Major && bump != semver.Minor && bump != semver.Patch {
+			return apiv1.Service{}, errors.New(customerrors.ErrInvalidVersionBump)
+		}
+		// bumped versions are releases.
+		if err := initLifecycle(service, false); err != nil {
+			return apiv1.Service{}, err
+		}
+		// the version is allocated by the repository so concurrent publishers never share one.
+		err := s.repo.AddNextVersion(service, bump)
+		if err != nil {
+			return apiv1.Service{}, err
+		}
+		service.Bump = ""
+	}
+	if version := service.SemVer(); version.Prerelease == "" {
+		err := s.repo.AdvanceCurrentVersion(model.NewCurrentVersion(service.Name, version))
+		if err != nil {
+			return apiv1.Service{}, err
+		}
+	}
+	details, err := s.serviceDetails(service.Name)
 	if err != nil {
 		return apiv1.Service{}, err
 	}
 	response = apiv1.Service{
-		TotalVersions:  len(oldVersions) + 1,
-		CurrentVersion: service.Version,
+		TotalVersions:  details.TotalVersions,
+		CurrentVersion: details.CurrentVersion,
 		Service:        service,
 	}
 	return response, nil
 }
 
-func UpdateVersion(service *model.Service) (*model.Service, error) {
-	_, err := FetchByVersionAndName(service.Name, service.Version)
+// Promote makes the given version the current version of the service.
+func (s *Service) Promote(name, version string) (apiv1.Service, error) {
+	var response apiv1.Service
+	err := s.transaction(func(tx *Service) error {
+		before, err := tx.snapshot(name)
+		if err != nil {
+			return err
+		}
+		if response, err = tx.promote(name, version); err != nil {
+			return err
+		}
+		return tx.audit(model.AuditPromote, name, response.CurrentVersion, before, response)
+	})
+	return response, err
+}
+
+func (s *Service) promote(name, version string) (apiv1.Service, error) {
+	service, err := s.FetchByVersionAndName(name, version)
+	if err != nil {
+		return apiv1.Service{}, err
+	}
+	err = s.repo.SetCurrentVersion(model.NewCurrentVersion(name, service.SemVer()))
 	if err != nil {
-		if err.Error() == customerrors.ErrServiceWithVersionNotFound {
-			return service, err
+		return apiv1.Service{}, err
+	}
+	return s.serviceDetails(name)
+}
+
+// Rollback points the service at the highest released version lower than its current version.
+func (s *Service) Rollback(name string) (apiv1.Service, error) {
+	var response apiv1.Service
+	err := s.transaction(func(tx *Service) error {
+		before, err := tx.snapshot(name)
+		if err != nil {
+			return err
 		}
-		return service, err
+		if response, err = tx.rollback(name); err != nil {
+			return err
+		}
+		return tx.audit(model.AuditRollback, name, response.CurrentVersion, before, response)
+	})
+	return response, err
+}
+
+func (s *Service) rollback(name string) (apiv1.Service, error) {
+	current, err := s.serviceDetails(name)
+	if err != nil {
+		return apiv1.Service{}, err
 	}
-	err = service.UpdateByNameAndVersion()
+	versions, err := s.FetchByName(name)
 	if err != nil {
-		return service, err
+		return apiv1.Service{}, err
+	}
+	var previous *model.Service
+	for i := range versions {
+		v := versions[i].SemVer()
+		if v.Prerelease == "" && v.Less(current.Service.SemVer()) {
+			previous = &versions[i]
+		}
+	}
+	if previous == nil {
+		return apiv1.Service{}, errors.New(customerrors.ErrNoPreviousVersion)
+	}
+	err = s.repo.SetCurrentVersion(model.NewCurrentVersion(name, previous.SemVer()))
+	if err != nil {
+		return apiv1.Service{}, err
+	}
+	return s.serviceDetails(name)
+}
+
+// UpdateVersion updates the fields of an existing version which are set and returns the version as it is stored.
+func (s *Service) UpdateVersion(service *model.Service) (*model.Service, error) {
+	return s.UpdateVersionIfMatch(service, nil)
+}
+
+// UpdateVersionIfMatch is UpdateVersion for a client which read the version at one of the given revisions,
+// it fails with ErrRevisionMismatch when the version is at another revision. Any revision matches when nil.
+func (s *Service) UpdateVersionIfMatch(service *model.Service, revisions []int) (*model.Service, error) {
+	var after model.Service
+	err := s.transaction(func(tx *Service) error {
+		before, err := tx.updateVersion(service, revisions)
+		if err != nil {
+			return err
+		}
+		if after, err = tx.repo.GetByNameAndVersion(service.Name, service.Version); err != nil {
+			return err
+		}
+		return tx.audit(model.AuditUpdateVersion, service.Name, service.Version, before, after)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return service, nil
+	return &after, nil
 }
 
-func Delete(name string) error {
-	_, err := FetchByName(name)
+// updateVersion updates the version, along with the named fields even when they are zero, and returns it as it was before.
+func (s *Service) updateVersion(service *model.Service, revisions []int, fields ...string) (model.Service, error) {
+	if err := s.validateOwner(service.Owner); err != nil {
+		return model.Service{}, err
+	}
+	if err := s.validateDependencies(service.Name, service.Dependencies); err != nil {
+		return model.Service{}, err
+	}
+	if err := s.validateSuccessor(service.Name, service.Successor); err != nil {
+		return model.Service{}, err
+	}
+	existing, err := s.FetchByVersionAndName(service.Name, service.Version)
+	if err != nil {
+		return model.Service{}, err
+	}
+	if err = deriveTags(service, existing.Labels); err != nil {
+		return model.Service{}, err
+	}
+	service.Version = existing.Version
+	// the repository only updates the version at the revision it was matched at.
+	service.Revision = 0
+	if revisions != nil {
+		if !slices.Contains(revisions, existing.Revision) {
+			return model.Service{}, errors.New(customerrors.ErrRevisionMismatch)
+		}
+		service.Revision = existing.Revision
+	}
+	if err = transitionLifecycle(existing, service); err != nil {
+		return model.Service{}, err
+	}
+	// the author is who published the version, fixing its release notes later does not change it.
+	service.Author = ""
+	err = s.repo.UpdateByNameAndVersion(service, fields...)
+	if err != nil {
+		return model.Service{}, err
+	}
+	return existing, nil
+}
+
+// Delete soft deletes all versions of the service.
+func (s *Service) Delete(name string) error {
+	return s.transaction(func(tx *Service) error {
+		versions, err := tx.FetchByName(name)
+		if err != nil {
+			return err
+		}
+		if err = tx.repo.DeleteByName(name); err != nil {
+			return err
+		}
+		return tx.audit(model.AuditDelete, name, "", versions, nil)
+	})
+}
+
+// DeleteVersion deletes a single version. The current version is only deleted with force,
+// the service is then pointed at its highest remaining release.
+func (s *Service) DeleteVersion(name, version string, force bool) error {
+	return s.transaction(func(tx *Service) error {
+		service, err := tx.FetchByVersionAndName(name, version)
+		if err != nil {
+			return err
+		}
+		if err = tx.deleteVersion(service, force); err != nil {
+			return err
+		}
+		return tx.audit(model.AuditDeleteVersion, name, service.Version, service, nil)
+	})
+}
+
+func (s *Service) deleteVersion(service model.Service, force bool) error {
+	name := service.Name
+	current, err := s.serviceDetails(name)
+	if err != nil {
+		return err
+	}
+	isCurrent := current.CurrentVersion == service.Version
+	if isCurrent && !force {
+		return errors.New(customerrors.ErrCurrentVersionDelete)
+	}
+	err = s.repo.DeleteByNameAndVersion(name, service.Version)
 	if err != nil {
 		return err
 	}
-	service := &model.Service{}
-	service.Name = name
-	err = service.DeleteByName()
+	if !isCurrent {
+		return nil
+	}
+	remaining, err := s.repo.GetByName(name)
 	if err != nil {
+		if err.Error() == customerrors.ErrServiceNotFound {
+			// the last version is gone, there is nothing left to point at.
+			return nil
+		}
 		return err
 	}
-	return nil
+	return s.repo.SetCurrentVersion(model.NewCurrentVersion(name, highestRelease(remaining)))
+}
+
+// FetchDeleted returns all soft deleted service versions.
+func (s *Service) FetchDeleted() ([]model.Service, error) {
+	deleted, err := s.repo.ListDeleted()
+	if err != nil {
+		return []model.Service{}, err
+	}
+	return deleted, nil
 }
 
-func FetchByName(name string) ([]model.Service, error) {
-	service := &model.Service{
-		Name: name,
+// Restore undoes the last deletion of the service, or of a single version when one is given.
+// It fails with ErrServiceRestoreConflict when a live service with the same name was created since.
+func (s *Service) Restore(name, version string) (apiv1.Service, error) {
+	var response apiv1.Service
+	err := s.transaction(func(tx *Service) error {
+		before, err := tx.snapshot(name)
+		if err != nil {
+			return err
+		}
+		if response, err = tx.restore(name, version); err != nil {
+			return err
+		}
+		var restored string
+		if version != "" {
+			// the version was validated by restore.
+			restored = semver.MustParse(version).String()
+		}
+		return tx.audit(model.AuditRestore, name, restored, before, response)
+	})
+	return response, err
+}
+
+func (s *Service) restore(name, version string) (apiv1.Service, error) {
+	deleted, err := s.repo.GetDeletedByName(name)
+	if err != nil {
+		return apiv1.Service{}, err
+	}
+	live, err := s.repo.GetByName(name)
+	if err != nil && err.Error() != customerrors.ErrServiceNotFound {
+		return apiv1.Service{}, err
+	}
+
+	var restore []model.Service
+	if version == "" {
+		if len(live) > 0 {
+			return apiv1.Service{}, errors.New(customerrors.ErrServiceRestoreConflict)
+		}
+		// all versions deleted together share the deletion time.
+		var last time.Time
+		for _, d := range deleted {
+			if d.DeletedAt.Time.After(last) {
+				last = d.DeletedAt.Time
+			}
+		}
+		for _, d := range deleted {
+			if d.DeletedAt.Time.Equal(last) {
+				restore = append(restore, d)
+			}
+		}
+	} else {
+		v, err := parseVersion(version)
+		if err != nil {
+			return apiv1.Service{}, err
+		}
+		var tombstone *model.Service
+		for i := range deleted {
+			if deleted[i].Version == v.String() && (tombstone == nil || deleted[i].DeletedAt.Time.After(tombstone.DeletedAt.Time)) {
+				tombstone = &deleted[i]
+			}
+		}
+		if tombstone == nil {
+			return apiv1.Service{}, errors.New(customerrors.ErrServiceWithVersionNotFound)
+		}
+		// either the version was published again or the whole service was re-created after the deletion.
+		for _, l := range live {
+			if l.Version == tombstone.Version || l.CreatedAt.After(tombstone.DeletedAt.Time) && isOldest(l, live) {
+				return apiv1.Service{}, errors.New(customerrors.ErrServiceRestoreConflict)
+			}
+		}
+		restore = append(restore, *tombstone)
+	}
+
+	ids := make([]uint, 0, len(restore))
+	for _, r := range restore {
+		ids = append(ids, r.ID)
+	}
+	err = s.repo.Restore(ids)
+	if err != nil {
+		if err.Error() == customerrors.ErrServiceVersionExists {
+			return apiv1.Service{}, errors.New(customerrors.ErrServiceRestoreConflict)
+		}
+		return apiv1.Service{}, err
+	}
+
+	// a restored service serves its highest release, a restored release is served like a newly published one.
+	if version == "" {
+		err = s.repo.SetCurrentVersion(model.NewCurrentVersion(name, highestRelease(restore)))
+	} else if v := restore[0].SemVer(); v.Prerelease == "" {
+		err = s.repo.AdvanceCurrentVersion(model.NewCurrentVersion(name, v))
 	}
-	services, err := service.GetByName()
+	if err != nil {
+		return apiv1.Service{}, err
+	}
+	return s.serviceDetails(name)
+}
+
+// FetchByName returns all versions of the service ordered by semver precedence.
+func (s *Service) FetchByName(name string) ([]model.Service, error) {
+	services, err := s.repo.GetByName(name)
 	if err != nil {
 		return []model.Service{}, err
 	}
 	return services, nil
 }
 
-func FetchByVersionAndName(name string, version int) (model.Service, error) {
-	service := &model.Service{
-		Name:    name,
-		Version: version,
+// FetchVersions returns the versions of the service matching the semver constraint, all of them if it is empty.
+func (s *Service) FetchVersions(name, constraint string) ([]model.Service, error) {
+	versions, err := s.FetchByName(name)
+	if err != nil {
+		return []model.Service{}, err
+	}
+	if constraint == "" {
+		return versions, nil
+	}
+	c, err := semver.ParseConstraint(constraint)
+	if err != nil {
+		return []model.Service{}, errors.New(customerrors.ErrInvalidVersionConstraint)
+	}
+	matching := []model.Service{}
+	for _, v := range versions {
+		if c.Check(v.SemVer()) {
+			matching = append(matching, v)
+		}
+	}
+	return matching, nil
+}
+
+// FetchByVersionAndName returns a single version, partial versions like 2 or 2.1 are read as 2.0.0 and 2.1.0.
+func (s *Service) FetchByVersionAndName(name string, version string) (model.Service, error) {
+	v, err := parseVersion(version)
+	if err != nil {
+		return model.Service{}, err
 	}
-	serviceFetched, err := service.GetByNameAndVersion()
+	serviceFetched, err := s.repo.GetByNameAndVersion(name, v.String())
 	if err != nil {
 		return model.Service{}, err
 	}
 	return serviceFetched, nil
 }
 
-func FetchAll() ([]model.Service, error) {
-	service := &model.Service{}
-	servicesFetched, err := service.List()
+func (s *Service) FetchAll() ([]model.Service, error) {
+	servicesFetched, err := s.repo.List()
 	if err != nil {
 		return []model.Service{}, err
 	}
 	return servicesFetched, nil
 }
 
-func SearchAndSort(query, sort, dir string, page, pageSize int) (apiv1.ServicePagination, error) {
-	service := model.Service{}
+// SearchAndSort returns a page of the listing, by page number or, when token is set, after or before the position of a
+// next or prev token of a previous page. Positions are keyed on the sort columns so walking the listing with tokens
+// neither skips nor repeats services while others are created or deleted.
+func (s *Service) SearchAndSort(search model.ServiceSearch, sort model.ServiceSort, page, pageSize int, token string) (apiv1.ServicePagination, error) {
 	var serviceDetailsHolder []apiv1.Service
-	serviceData, totalCount, err := service.GetServiceAndVersionCounts(query, pageSize, page, sort, dir)
+	// one more service is fetched to know whether there is a page after this one.
+	request := model.ServicePage{Limit: pageSize + 1, Offset: (page - 1) * pageSize}
+	var position pageCursor
+	if token != "" {
+		if err := s.cursors.Decode(token, &position); err != nil || position.Sort != sort.String() {
+			return apiv1.ServicePagination{}, errors.New(customerrors.ErrInvalidCursor)
+		}
+		if position.Before {
+			request.Before = &position.Position
+		} else {
+			request.After = &position.Position
+		}
+		page = 0
+	}
+	serviceData, totalCount, err := s.repo.GetServiceAndVersionCounts(search, sort, request)
 	if err != nil {
 		return apiv1.ServicePagination{}, err
 	}
-	for _, s := range serviceData {
-		details, err := serviceDetails(s.Name)
-		if err != nil {
-			return apiv1.ServicePagination{}, err
+	var hasNext, hasPrev bool
+	if request.Before != nil {
+		// walking back, the extra service is the first one.
+		hasNext = true
+		if len(serviceData) > pageSize {
+			serviceData, hasPrev = serviceData[1:], true
+		}
+	} else {
+		hasPrev = page > 1 || request.After != nil
+		if len(serviceData) > pageSize {
+			serviceData, hasNext = serviceData[:pageSize], true
+		}
+	}
+
+	// the details of the whole page are fetched at once and put back in the order of the page.
+	names := make([]string, len(serviceData))
+	for i, sc := range serviceData {
+		names[i] = sc.Name
+	}
+	summaries, err := s.repo.GetSummaries(names)
+	if err != nil {
+		return apiv1.ServicePagination{}, err
+	}
+	byName := map[string]model.ServiceSummary{}
+	for _, summary := range summaries {
+		byName[summary.Name] = summary
+	}
+	for _, name := range names {
+		if summary, ok := byName[name]; ok {
+			serviceDetailsHolder = append(serviceDetailsHolder, summaryResponse(summary))
 		}
-		serviceDetailsHolder = append(serviceDetailsHolder, details)
 	}
 	response := apiv1.ServicePagination{
 		Meta: apiv1.Meta{
@@ -136,23 +609,92 @@ func SearchAndSort(query, sort, dir string, page, pageSize int) (apiv1.ServicePa
 		},
 		Data: serviceDetailsHolder,
 	}
+	if hasNext {
+		response.Meta.Next, err = s.cursors.Encode(pageCursor{Sort: sort.String(), Position: serviceData[len(serviceData)-1]})
+		if err != nil {
+			return apiv1.ServicePagination{}, err
+		}
+	}
+	if hasPrev {
+		response.Meta.Prev, err = s.cursors.Encode(pageCursor{Sort: sort.String(), Before: true, Position: serviceData[0]})
+		if err != nil {
+			return apiv1.ServicePagination{}, err
+		}
+	}
 	return response, err
 }
 
-func serviceDetails(name string) (apiv1.Service, error) {
-	var response apiv1.Service
-	Versions, err := FetchByName(name)
+// serviceDetails describes the service by its current version.
+func (s *Service) serviceDetails(name string) (apiv1.Service, error) {
+	summaries, err := s.repo.GetSummaries([]string{name})
 	if err != nil {
 		return apiv1.Service{}, err
 	}
-	service, err := FetchByVersionAndName(name, len(Versions))
+	if len(summaries) == 0 {
+		return apiv1.Service{}, errors.New(customerrors.ErrServiceNotFound)
+	}
+	return summaryResponse(summaries[0]), nil
+}
+
+// summaryResponse returns the response for the version of a service served to clients.
+func summaryResponse(summary model.ServiceSummary) apiv1.Service {
+	return apiv1.Service{
+		TotalVersions:  summary.TotalVersions,
+		CurrentVersion: summary.Version,
+		Service:        &summary.Service,
+	}
+}
+
+// deriveTags validates the labels of a version being written and derives its tags from them. Tags written by
+// clients are rejected unless they are the tags of the labels the version ends up with, the kept labels when
+// none are written.
+func deriveTags(service *model.Service, kept model.Labels) error {
+	if err := service.Labels.Validate(); err != nil {
+		return errors.New(customerrors.ErrInvalidLabels)
+	}
+	labels := service.Labels
+	if labels == nil {
+		labels = kept
+	}
+	if service.Tags != "" && service.Tags != labels.Tags() {
+		return errors.New(customerrors.ErrReadOnlyTags)
+	}
+	service.Tags = service.Labels.Tags()
+	return nil
+}
+
+func parseVersion(version string) (semver.Version, error) {
+	v, err := semver.Parse(version)
 	if err != nil {
-		return apiv1.Service{}, nil
+		return semver.Version{}, errors.New(customerrors.ErrInvalidVersion)
 	}
-	response = apiv1.Service{
-		TotalVersions:  len(Versions),
-		CurrentVersion: len(Versions),
-		Service:        &service,
+	return v, nil
+}
+
+// isOldest reports whether v is the first created of the versions.
+func isOldest(v model.Service, versions []model.Service) bool {
+	for _, o := range versions {
+		if o.CreatedAt.Before(v.CreatedAt) {
+			return false
+		}
 	}
-	return response, nil
+	return true
+}
+
+// highestRelease returns the highest version which is not a prerelease, the highest prerelease if there is none.
+func highestRelease(versions []model.Service) semver.Version {
+	highest := versions[0].SemVer()
+	for _, v := range versions[1:] {
+		sv := v.SemVer()
+		if (sv.Prerelease == "") != (highest.Prerelease == "") {
+			if sv.Prerelease == "" {
+				highest = sv
+			}
+			continue
+		}
+		if highest.Less(sv) {
+			highest = sv
+		}
+	}
+	return highest
 }
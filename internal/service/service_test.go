@@ -0,0 +1,488 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	"github.com/suyog1pathak/services/pkg/cursor"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"github.com/suyog1pathak/services/pkg/semver"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestService() *Service {
+	return NewService(model.NewMemoryServiceRepository(), model.NewMemoryTeamRepository(), cursor.NewCodec([]byte("secret")))
+}
+
+func TestCreateShouldRejectDuplicateName(t *testing.T) {
+	s := newTestService()
+	_, err := s.Create(&model.Service{Name: "payments", IsActive: true})
+	assert.NoError(t, err)
+
+	_, err = s.Create(&model.Service{Name: "payments"})
+	assert.EqualError(t, err, customerrors.ErrServiceFoundWithSameName)
+}
+
+func TestCreateVersionShouldIncrementVersion(t *testing.T) {
+	s := newTestService()
+	_, err := s.Create(&model.Service{Name: "payments"})
+	assert.NoError(t, err)
+
+	response, err := s.CreateVersion(&model.Service{Name: "payments", Description: "v2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0", response.CurrentVersion)
+	assert.Equal(t, 2, response.TotalVersions)
+
+	response, err = s.CreateVersion(&model.Service{Name: "payments", Bump: semver.Minor})
+	assert.NoError(t, err)
+	assert.Equal(t, "2.1.0", response.CurrentVersion)
+
+	response, err = s.CreateVersion(&model.Service{Name: "payments", Bump: semver.Patch})
+	assert.NoError(t, err)
+	assert.Equal(t, "2.1.1", response.CurrentVersion)
+
+	_, err = s.CreateVersion(&model.Service{Name: "payments", Bump: "build"})
+	assert.EqualError(t, err, customerrors.ErrInvalidVersionBump)
+
+	_, err = s.CreateVersion(&model.Service{Name: "orders"})
+	assert.EqualError(t, err, customerrors.ErrServiceNotFound)
+}
+
+func TestDeleteShouldRemoveAllVersions(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments"})
+
+	assert.NoError(t, s.Delete("payments"))
+	_, err := s.FetchByName("payments")
+	assert.EqualError(t, err, customerrors.ErrServiceNotFound)
+	assert.EqualError(t, s.Delete("payments"), customerrors.ErrServiceNotFound)
+}
+
+func TestCreateVersionShouldAllocateDistinctVersionsConcurrently(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+
+	var wg sync.WaitGroup
+	versions := make([]int, 20)
+	for i := range versions {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			response, err := s.CreateVersion(&model.Service{Name: "payments"})
+			assert.NoError(t, err)
+			versions[i] = response.Service.SemVer().Major
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Ints(versions)
+	for i, v := range versions {
+		assert.Equal(t, i+2, v)
+	}
+}
+
+func TestCreateVersionShouldAcceptExplicitVersion(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments", Version: "1.4.0"})
+
+	response, err := s.CreateVersion(&model.Service{Name: "payments", Version: "2.0.0-rc.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0-rc.1", response.Service.Version)
+
+	response, err = s.CreateVersion(&model.Service{Name: "payments", Version: "v1.4.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.4.1", response.Service.Version)
+
+	_, err = s.CreateVersion(&model.Service{Name: "payments", Version: "1.4.1"})
+	assert.EqualError(t, err, customerrors.ErrServiceVersionExists)
+	_, err = s.CreateVersion(&model.Service{Name: "payments", Version: "1.4"})
+	assert.EqualError(t, err, customerrors.ErrServiceVersionExists)
+	_, err = s.CreateVersion(&model.Service{Name: "payments", Version: "latest"})
+	assert.EqualError(t, err, customerrors.ErrInvalidVersion)
+	_, err = s.CreateVersion(&model.Service{Name: "payments", Version: "3.0.0", Bump: semver.Patch})
+	assert.EqualError(t, err, customerrors.ErrInvalidVersionBump)
+
+	// bumping a prerelease releases it.
+	response, err = s.CreateVersion(&model.Service{Name: "payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0", response.CurrentVersion)
+}
+
+func TestFetchVersionsShouldFilterByConstraint(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments", Version: "1.2.0"})
+	for _, v := range []string{"1.10.0", "1.3.0-beta", "2.0.0", "1.1.0"} {
+		_, err := s.CreateVersion(&model.Service{Name: "payments", Version: v})
+		assert.NoError(t, err)
+	}
+
+	versions, err := s.FetchVersions("payments", "^1.2")
+	assert.NoError(t, err)
+	var got []string
+	for _, v := range versions {
+		got = append(got, v.Version)
+	}
+	assert.Equal(t, []string{"1.2.0", "1.10.0"}, got)
+
+	_, err = s.FetchVersions("payments", "^^1")
+	assert.EqualError(t, err, customerrors.ErrInvalidVersionConstraint)
+
+	fetched, err := s.FetchByVersionAndName("payments", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0", fetched.Version)
+}
+
+func TestCurrentVersionShouldBePromotedAndRolledBack(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments", Bump: semver.Minor})
+
+	// prereleases are not served until promoted.
+	response, err := s.CreateVersion(&model.Service{Name: "payments", Version: "2.0.0-rc.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.0", response.CurrentVersion)
+
+	response, err = s.Promote("payments", "2.0.0-rc.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0-rc.1", response.CurrentVersion)
+	assert.Equal(t, "2.0.0-rc.1", response.Service.Version)
+	assert.Equal(t, 3, response.TotalVersions)
+
+	response, err = s.Rollback("payments")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.0", response.CurrentVersion)
+
+	response, err = s.Rollback("payments")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", response.CurrentVersion)
+
+	_, err = s.Rollback("payments")
+	assert.EqualError(t, err, customerrors.ErrNoPreviousVersion)
+	_, err = s.Promote("payments", "9.0.0")
+	assert.EqualError(t, err, customerrors.ErrServiceWithVersionNotFound)
+
+	// a release above the current version is served right away.
+	response, err = s.CreateVersion(&model.Service{Name: "payments", Version: "1.0.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.1", response.CurrentVersion)
+}
+
+func TestDeleteVersionShouldGuardAndRepointCurrentVersion(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments", Bump: semver.Minor})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments", Version: "1.2.0-rc.1"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments", Bump: semver.Major})
+
+	assert.EqualError(t, s.DeleteVersion("payments", "2.0.0", false), customerrors.ErrCurrentVersionDelete)
+	assert.NoError(t, s.DeleteVersion("payments", "1.0.0", false))
+	assert.EqualError(t, s.DeleteVersion("payments", "1.0.0", false), customerrors.ErrServiceWithVersionNotFound)
+
+	// the prerelease is skipped when the current version is re-pointed.
+	assert.NoError(t, s.DeleteVersion("payments", "2.0.0", true))
+	details, err := s.serviceDetails("payments")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.0", details.CurrentVersion)
+	assert.Equal(t, 2, details.TotalVersions)
+}
+
+func TestRestoreShouldUndoTheLastDeletion(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments"})
+	assert.NoError(t, s.DeleteVersion("payments", "1.0.0", false))
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, s.Delete("payments"))
+
+	deleted, err := s.FetchDeleted()
+	assert.NoError(t, err)
+	assert.Len(t, deleted, 3)
+
+	// the version deleted before the service stays deleted.
+	response, err := s.Restore("payments", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.TotalVersions)
+	assert.Equal(t, "3.0.0", response.CurrentVersion)
+
+	_, err = s.Restore("payments", "")
+	assert.EqualError(t, err, customerrors.ErrServiceRestoreConflict)
+
+	response, err = s.Restore("payments", "1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, response.TotalVersions)
+	assert.Equal(t, "3.0.0", response.CurrentVersion)
+
+	_, err = s.Restore("orders", "")
+	assert.EqualError(t, err, customerrors.ErrDeletedServiceNotFound)
+}
+
+func TestNextVersionShouldNotReuseTheNumberOfADeletedVersion(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments"})
+	assert.NoError(t, s.DeleteVersion("payments", "2.0.0", true))
+
+	response, err := s.CreateVersion(&model.Service{Name: "payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, "3.0.0", response.Version)
+	response, err = s.Restore("payments", "2.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, response.TotalVersions)
+}
+
+func TestRestoreShouldConflictWithRecreatedService(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments"})
+	assert.NoError(t, s.Delete("payments"))
+	time.Sleep(time.Millisecond)
+	_, _ = s.Create(&model.Service{Name: "payments"})
+
+	_, err := s.Restore("payments", "2.0.0")
+	assert.EqualError(t, err, customerrors.ErrServiceRestoreConflict)
+	_, err = s.Restore("payments", "1.0.0")
+	assert.EqualError(t, err, customerrors.ErrServiceRestoreConflict)
+	_, err = s.Restore("payments", "")
+	assert.EqualError(t, err, customerrors.ErrServiceRestoreConflict)
+}
+
+func TestSearchAndSortShouldMatchNameDescriptionAndTags(t *testing.T) {
+	s := newTestService()
+	// tags are derived from the labels.
+	_, _ = s.Create(&model.Service{Name: "payments", Description: "Card processing", Labels: model.NewLabels(map[string]string{"billing": "", "pci": ""})})
+	_, _ = s.Create(&model.Service{Name: "orders", Description: "Order intake for payments", Labels: model.NewLabels(map[string]string{"checkout": ""})})
+	_, _ = s.Create(&model.Service{Name: "Notifications", Description: "Email and SMS", Labels: model.NewLabels(map[string]string{"messaging": "", "email": ""})})
+
+	names := func(query, match, fields string) []string {
+		search, err := model.NewServiceSearch(query, match, fields)
+		assert.NoError(t, err)
+		response, err := s.SearchAndSort(search, model.ServiceSort{{Key: model.SortName}}, 1, 10, "")
+		if err != nil {
+			assert.EqualError(t, err, customerrors.ErrServiceNotFound)
+			return nil
+		}
+		var got []string
+		for _, service := range response.Data {
+			got = append(got, service.Name)
+		}
+		return got
+	}
+
+	assert.Equal(t, []string{"Notifications", "orders", "payments"}, names("", "", ""))
+	assert.Equal(t, []string{"orders", "payments"}, names("PAYMENT", "", ""))
+	assert.Equal(t, []string{"payments"}, names("payment", "", "name"))
+	assert.Equal(t, []string{"payments"}, names("pay", "prefix", ""))
+	assert.Equal(t, []string{"Notifications"}, names("notif", "prefix", "name,description"))
+	assert.Equal(t, []string{"payments"}, names("pc", "prefix", "tags"))
+	assert.Equal(t, []string{"Notifications"}, names("ema", "prefix", "tags"))
+	assert.Nil(t, names("ill", "prefix", "tags"))
+	assert.Nil(t, names("100%", "", ""))
+
+	_, err := model.NewServiceSearch("pay", "regex", "")
+	assert.Error(t, err)
+	_, err = model.NewServiceSearch("pay", "", "owner")
+	assert.Error(t, err)
+}
+
+func TestSearchAndSortShouldOrderByMultipleKeys(t *testing.T) {
+	s := newTestService()
+	for _, name := range []string{"billing", "orders", "payments", "audit"} {
+		_, _ = s.Create(&model.Service{Name: name})
+	}
+	_, _ = s.CreateVersion(&model.Service{Name: "orders"})
+	_, _ = s.CreateVersion(&model.Service{Name: "payments"})
+
+	names := func(keys, dir string) []string {
+		sort, err := model.ParseServiceSort(keys, dir)
+		assert.NoError(t, err)
+		search, _ := model.NewServiceSearch("", "", "")
+		response, err := s.SearchAndSort(search, sort, 1, 10, "")
+		assert.NoError(t, err)
+		var got []string
+		for _, service := range response.Data {
+			got = append(got, service.Name)
+		}
+		return got
+	}
+
+	assert.Equal(t, []string{"orders", "payments", "audit", "billing"}, names("-version_count,+name", ""))
+	assert.Equal(t, []string{"audit", "billing", "orders", "payments"}, names("version_count", "asc"))
+	assert.Equal(t, []string{"payments", "orders", "billing", "audit"}, names("name", "desc"))
+	assert.Equal(t, []string{"payments", "orders", "billing", "audit"}, names("-name", "asc"))
+
+	for _, tc := range []struct{ keys, dir string }{
+		{"name; DROP TABLE services", ""},
+		{"owner", ""},
+		{"name,-name", ""},
+		{"name", "sideways"},
+	} {
+		_, err := model.ParseServiceSort(tc.keys, tc.dir)
+		assert.Error(t, err, tc.keys)
+	}
+}
+
+func TestSearchAndSortShouldWalkWithCursors(t *testing.T) {
+	s := newTestService()
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		_, _ = s.Create(&model.Service{Name: name})
+	}
+	search, _ := model.NewServiceSearch("", "", "")
+	sort, _ := model.ParseServiceSort("name", "asc")
+	names := func(response apiv1.ServicePagination) []string {
+		var got []string
+		for _, service := range response.Data {
+			got = append(got, service.Name)
+		}
+		return got
+	}
+
+	first, err := s.SearchAndSort(search, sort, 1, 2, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names(first))
+	assert.Empty(t, first.Meta.Prev)
+
+	// a service created before the position does not shift the next page.
+	_, _ = s.Create(&model.Service{Name: "aa"})
+	second, err := s.SearchAndSort(search, sort, 1, 2, first.Meta.Next)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, names(second))
+	assert.Equal(t, 6, second.Meta.TotalResults)
+
+	last, err := s.SearchAndSort(search, sort, 1, 2, second.Meta.Next)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"e"}, names(last))
+	assert.Empty(t, last.Meta.Next)
+
+	back, err := s.SearchAndSort(search, sort, 1, 2, second.Meta.Prev)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"aa", "b"}, names(back))
+	assert.NotEmpty(t, back.Meta.Prev)
+	assert.NotEmpty(t, back.Meta.Next)
+
+	// cursors are signed and only valid for the sort they were created for.
+	_, err = s.SearchAndSort(search, sort, 1, 2, first.Meta.Next+"x")
+	assert.EqualError(t, err, customerrors.ErrInvalidCursor)
+	desc, _ := model.ParseServiceSort("name", "desc")
+	_, err = s.SearchAndSort(search, desc, 1, 2, first.Meta.Next)
+	assert.EqualError(t, err, customerrors.ErrInvalidCursor)
+}
+
+// names returns the names of the services of a page.
+func names(services []apiv1.Service) []string {
+	var got []string
+	for _, service := range services {
+		got = append(got, service.Name)
+	}
+	return got
+}
+
+func TestLabelsShouldDeriveTags(t *testing.T) {
+	s := newTestService()
+	_, err := s.Create(&model.Service{Name: "payments", Tags: "tier=1", Labels: model.NewLabels(map[string]string{"tier": "1", "pci": ""})})
+	assert.EqualError(t, err, customerrors.ErrReadOnlyTags)
+	// the tags of the labels are accepted, as a client writes back a version it read.
+	response, err := s.Create(&model.Service{Name: "payments", Tags: "pci,tier=1", Labels: model.NewLabels(map[string]string{"tier": "1", "pci": ""})})
+	assert.NoError(t, err)
+	assert.Equal(t, "pci,tier=1", response.Tags)
+	_, err = s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Description: "cards", Tags: "pci,tier=1"})
+	assert.NoError(t, err)
+	_, err = s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Tags: "tier=2"})
+	assert.EqualError(t, err, customerrors.ErrReadOnlyTags)
+
+	updated, err := s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Labels: model.NewLabels(map[string]string{"tier": "2"})})
+	assert.NoError(t, err)
+	assert.Equal(t, "tier=2", updated.Tags)
+	fetched, _ := s.FetchByVersionAndName("payments", "1.0.0")
+	assert.Equal(t, map[string]string{"tier": "2"}, fetched.Labels.Map())
+	assert.Equal(t, "tier=2", fetched.Tags)
+
+	// labels left out are kept, an empty object clears them.
+	_, _ = s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Description: "cards"})
+	fetched, _ = s.FetchByVersionAndName("payments", "1.0.0")
+	assert.Equal(t, "tier=2", fetched.Tags)
+	_, _ = s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Labels: model.Labels{}})
+	fetched, _ = s.FetchByVersionAndName("payments", "1.0.0")
+	assert.Empty(t, fetched.Labels)
+	assert.Empty(t, fetched.Tags)
+
+	_, err = s.Create(&model.Service{Name: "orders", Labels: model.NewLabels(map[string]string{"team name": "core"})})
+	assert.EqualError(t, err, customerrors.ErrInvalidLabels)
+}
+
+func TestSearchAndSortShouldSelectByLabels(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments", Labels: model.NewLabels(map[string]string{"tier": "1", "team": "payments", "pci": ""})})
+	_, _ = s.Create(&model.Service{Name: "ledger", Labels: model.NewLabels(map[string]string{"tier": "1", "team": "core"})})
+	_, _ = s.Create(&model.Service{Name: "search", Labels: model.NewLabels(map[string]string{"tier": "2", "team": "discovery"})})
+	_, _ = s.Create(&model.Service{Name: "legacy"})
+
+	selected := func(selector string) []string {
+		labels, err := model.ParseLabelSelector(selector)
+		assert.NoError(t, err)
+		response, err := s.SearchAndSort(model.ServiceSearch{Labels: labels}, model.ServiceSort{{Key: model.SortName}}, 1, 10, "")
+		if err != nil {
+			assert.EqualError(t, err, customerrors.ErrServiceNotFound)
+			return nil
+		}
+		return names(response.Data)
+	}
+
+	assert.Equal(t, []string{"ledger", "payments"}, selected("tier=1"))
+	assert.Equal(t, []string{"ledger", "payments"}, selected("tier==1,team in (payments, core)"))
+	assert.Equal(t, []string{"legacy", "search"}, selected("tier!=1"))
+	assert.Equal(t, []string{"payments"}, selected("pci"))
+	assert.Equal(t, []string{"ledger", "legacy", "search"}, selected("!pci"))
+	assert.Equal(t, []string{"search"}, selected("tier,team notin (payments,core)"))
+	assert.Nil(t, selected("tier=3"))
+
+	for _, selector := range []string{"tier=1,", "team in payments", "team in (a b)", "=1", "tier=1=2"} {
+		_, err := model.ParseLabelSelector(selector)
+		assert.Error(t, err, selector)
+	}
+}
+
+func TestUpdateVersionShouldBumpAndMatchRevision(t *testing.T) {
+	s := newTestService()
+	created, _ := s.Create(&model.Service{Name: "payments"})
+	assert.Equal(t, 1, created.Revision)
+
+	updated, err := s.UpdateVersionIfMatch(&model.Service{Name: "payments", Version: "1.0.0", Description: "Payments"}, []int{1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated.Revision)
+
+	// the second editor read the version at revision 1 as well.
+	_, err = s.UpdateVersionIfMatch(&model.Service{Name: "payments", Version: "1.0.0", Description: "Card payments"}, []int{1})
+	assert.EqualError(t, err, customerrors.ErrRevisionMismatch)
+	_, err = s.UpdateVersionIfMatch(&model.Service{Name: "payments", Version: "1.0.0", Description: "Card payments"}, []int{})
+	assert.EqualError(t, err, customerrors.ErrRevisionMismatch)
+	version, _ := s.FetchByVersionAndName("payments", "1.0.0")
+	assert.Equal(t, "Payments", version.Description)
+	assert.Equal(t, 2, version.Revision)
+
+	// a revision sent in the body is ignored.
+	updated, err = s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Description: "Card payments", Revision: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, updated.Revision)
+	next, _ := s.CreateVersion(&model.Service{Name: "payments"})
+	assert.Equal(t, 1, next.Revision)
+}
+
+func TestUpdateVersionShouldReturnTheStoredVersion(t *testing.T) {
+	s := newTestService()
+	_, _ = s.Create(&model.Service{Name: "payments", Description: "Payments", Labels: model.Labels{{Key: "tier", Value: "1"}}})
+
+	updated, err := s.UpdateVersion(&model.Service{Name: "payments", Version: "1.0.0", Changelog: "Adds refunds."})
+	assert.NoError(t, err)
+	stored, _ := s.FetchByVersionAndName("payments", "1.0.0")
+	assert.Equal(t, stored, *updated)
+	assert.NotZero(t, updated.ID)
+	assert.Equal(t, "Payments", updated.Description)
+	assert.Equal(t, model.LifecycleProduction, updated.Lifecycle)
+	assert.True(t, updated.IsActive)
+	assert.Equal(t, 2, updated.Revision)
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"regexp"
+	"strings"
+)
+
+// teamName is the format of a team name, it is used in URLs and stored as the owner of services.
+var teamName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,48}[a-z0-9])?$`)
+
+func (s *Service) CreateTeam(team *model.Team) (model.Team, error) {
+	if !teamName.MatchString(team.Name) {
+		return model.Team{}, errors.New(customerrors.ErrInvalidTeam)
+	}
+	seen := map[string]bool{}
+	for _, o := range team.Owners {
+		email := strings.ToLower(o.Email)
+		if seen[email] {
+			return model.Team{}, errors.New(customerrors.ErrInvalidTeam)
+		}
+		seen[email] = true
+	}
+	err := s.transaction(func(tx *Service) error {
+		if err := tx.teams.Add(team); err != nil {
+			return err
+		}
+		return tx.auditTeam(model.AuditCreateTeam, team.Name, nil, team)
+	})
+	if err != nil {
+		return model.Team{}, err
+	}
+	return *team, nil
+}
+
+func (s *Service) FetchTeams() ([]model.Team, error) {
+	return s.teams.List()
+}
+
+func (s *Service) FetchTeam(name string) (model.Team, error) {
+	return s.teams.GetByName(name)
+}
+
+// UpdateTeam updates the description of the team, owners are managed with AddOwner and RemoveOwner.
+func (s *Service) UpdateTeam(team *model.Team) (model.Team, error) {
+	var response model.Team
+	err := s.transaction(func(tx *Service) error {
+		before, err := tx.teams.GetByName(team.Name)
+		if err != nil {
+			return err
+		}
+		if err = tx.teams.Update(team); err != nil {
+			return err
+		}
+		if response, err = tx.teams.GetByName(team.Name); err != nil {
+			return err
+		}
+		return tx.auditTeam(model.AuditUpdateTeam, team.Name, before, response)
+	})
+	if err != nil {
+		return model.Team{}, err
+	}
+	return response, nil
+}
+
+// DeleteTeam deletes a team which no longer owns any live service.
+func (s *Service) DeleteTeam(name string) error {
+	return s.transaction(func(tx *Service) error {
+		before, err := tx.teams.GetByName(name)
+		if err != nil {
+			return err
+		}
+		owned, err := tx.ownsServices(name)
+		if err != nil {
+			return err
+		}
+		if owned {
+			return errors.New(customerrors.ErrTeamOwnsServices)
+		}
+		if err = tx.teams.Delete(name); err != nil {
+			return err
+		}
+		return tx.auditTeam(model.AuditDeleteTeam, name, before, nil)
+	})
+}
+
+func (s *Service) AddOwner(name string, owner *model.Owner) (model.Team, error) {
+	var response model.Team
+	err := s.transaction(func(tx *Service) error {
+		team, err := tx.teams.GetByName(name)
+		if err != nil {
+			return err
+		}
+		owner.TeamID = team.ID
+		if err = tx.teams.AddOwner(owner); err != nil {
+			return err
+		}
+		if response, err = tx.teams.GetByName(name); err != nil {
+			return err
+		}
+		return tx.auditTeam(model.AuditAddOwner, name, nil, owner)
+	})
+	if err != nil {
+		return model.Team{}, err
+	}
+	return response, nil
+}
+
+func (s *Service) RemoveOwner(name, email string) error {
+	return s.transaction(func(tx *Service) error {
+		team, err := tx.teams.GetByName(name)
+		if err != nil {
+			return err
+		}
+		if err = tx.teams.DeleteOwner(team.ID, email); err != nil {
+			return err
+		}
+		var before interface{}
+		for _, o := range team.Owners {
+			if strings.EqualFold(o.Email, email) {
+				before = o
+			}
+		}
+		return tx.auditTeam(model.AuditRemoveOwner, name, before, nil)
+	})
+}
+
+// FetchTeamServices is SearchAndSort limited to the services owned by the team.
+func (s *Service) FetchTeamServices(name string, search model.ServiceSearch, sort model.ServiceSort, page, pageSize int, token string) (apiv1.ServicePagination, error) {
+	_, err := s.teams.GetByName(name)
+	if err != nil {
+		return apiv1.ServicePagination{}, err
+	}
+	search.Owners = []string{name}
+	return s.SearchAndSort(search, sort, page, pageSize, token)
+}
+
+func (s *Service) ownsServices(name string) (bool, error) {
+	search := model.ServiceSearch{Owners: []string{name}}
+	_, _, err := s.repo.GetServiceAndVersionCounts(search, model.ServiceSort{{Key: model.SortName}}, model.ServicePage{Limit: 1})
+	if err != nil {
+		if err.Error() == customerrors.ErrServiceNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// validateOwner checks the owner of a service being written is an existing team.
+func (s *Service) validateOwner(owner model.OwnerName) error {
+	if owner == "" {
+		return nil
+	}
+	_, err := s.teams.GetByName(string(owner))
+	if err != nil {
+		if err.Error() == customerrors.ErrTeamNotFound {
+			return errors.New(customerrors.ErrUnknownOwner)
+		}
+		return err
+	}
+	return nil
+}
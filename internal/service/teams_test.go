@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/model"
+	"testing"
+)
+
+func TestCreateTeamShouldValidateName(t *testing.T) {
+	s := newTestService()
+	_, err := s.CreateTeam(&model.Team{Name: "payments-platform", Owners: []model.Owner{{Email: "jane@example.com"}}})
+	assert.NoError(t, err)
+
+	_, err = s.CreateTeam(&model.Team{Name: "payments-platform"})
+	assert.EqualError(t, err, customerrors.ErrTeamFoundWithSameName)
+
+	_, err = s.CreateTeam(&model.Team{Name: "Payments Platform"})
+	assert.EqualError(t, err, customerrors.ErrInvalidTeam)
+
+	_, err = s.CreateTeam(&model.Team{Name: "orders", Owners: []model.Owner{{Email: "joe@example.com"}, {Email: "JOE@example.com"}}})
+	assert.EqualError(t, err, customerrors.ErrInvalidTeam)
+}
+
+func TestServiceOwnerShouldBeAnExistingTeam(t *testing.T) {
+	s := newTestService()
+	_, _ = s.CreateTeam(&model.Team{Name: "payments-platform"})
+
+	_, err := s.Create(&model.Service{Name: "payments", Owner: "unknown"})
+	assert.EqualError(t, err, customerrors.ErrUnknownOwner)
+
+	_, err = s.Create(&model.Service{Name: "payments", Owner: "payments-platform"})
+	assert.NoError(t, err)
+
+	response, err := s.CreateVersion(&model.Service{Name: "payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.OwnerName("payments-platform"), response.Owner)
+
+	_, err = s.CreateVersion(&model.Service{Name: "payments", Owner: "unknown"})
+	assert.EqualError(t, err, customerrors.ErrUnknownOwner)
+}
+
+func TestFetchTeamServicesShouldFilterByOwner(t *testing.T) {
+	s := newTestService()
+	_, _ = s.CreateTeam(&model.Team{Name: "payments-platform"})
+	_, _ = s.CreateTeam(&model.Team{Name: "orders-platform"})
+	_, _ = s.Create(&model.Service{Name: "payments", Owner: "payments-platform"})
+	_, _ = s.Create(&model.Service{Name: "refunds", Owner: "payments-platform"})
+	_, _ = s.Create(&model.Service{Name: "orders", Owner: "orders-platform"})
+	_, _ = s.Create(&model.Service{Name: "search"})
+
+	response, err := s.FetchTeamServices("payments-platform", model.ServiceSearch{}, model.ServiceSort{{Key: model.SortName}}, 1, 10, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"payments", "refunds"}, names(response.Data))
+
+	response, err = s.SearchAndSort(model.ServiceSearch{Owners: []string{"orders-platform", "payments-platform"}}, model.ServiceSort{{Key: model.SortName}}, 1, 10, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orders", "payments", "refunds"}, names(response.Data))
+
+	_, err = s.FetchTeamServices("unknown", model.ServiceSearch{}, model.ServiceSort{{Key: model.SortName}}, 1, 10, "")
+	assert.EqualError(t, err, customerrors.ErrTeamNotFound)
+}
+
+func TestDeleteTeamShouldRejectTeamOwningServices(t *testing.T) {
+	s := newTestService()
+	_, _ = s.CreateTeam(&model.Team{Name: "payments-platform"})
+	_, _ = s.Create(&model.Service{Name: "payments", Owner: "payments-platform"})
+
+	assert.EqualError(t, s.DeleteTeam("payments-platform"), customerrors.ErrTeamOwnsServices)
+
+	assert.NoError(t, s.Delete("payments"))
+	assert.NoError(t, s.DeleteTeam("payments-platform"))
+	assert.EqualError(t, s.DeleteTeam("payments-platform"), customerrors.ErrTeamNotFound)
+}
+
+func TestOwnersShouldBeAddedAndRemoved(t *testing.T) {
+	s := newTestService()
+	_, _ = s.CreateTeam(&model.Team{Name: "payments-platform"})
+
+	team, err := s.AddOwner("payments-platform", &model.Owner{Name: "Jane", Email: "jane@example.com"})
+	assert.NoError(t, err)
+	assert.Len(t, team.Owners, 1)
+
+	_, err = s.AddOwner("payments-platform", &model.Owner{Email: "Jane@example.com"})
+	assert.EqualError(t, err, customerrors.ErrOwnerExists)
+
+	assert.NoError(t, s.RemoveOwner("payments-platform", "jane@example.com"))
+	assert.EqualError(t, s.RemoveOwner("payments-platform", "jane@example.com"), customerrors.ErrOwnerNotFound)
+
+	team, _ = s.FetchTeam("payments-platform")
+	assert.Empty(t, team.Owners)
+}
+
+func TestTeamChangesShouldBeAudited(t *testing.T) {
+	s := newTestService()
+	jane := s.As(Caller{Actor: "jane.doe"})
+	_, err := jane.CreateTeam(&model.Team{Name: "payments-platform"})
+	assert.NoError(t, err)
+	_, err = jane.UpdateTeam(&model.Team{Name: "payments-platform", Description: "Payments"})
+	assert.NoError(t, err)
+	_, err = jane.AddOwner("payments-platform", &model.Owner{Name: "Jane", Email: "jane@example.com"})
+	assert.NoError(t, err)
+	assert.NoError(t, jane.RemoveOwner("payments-platform", "Jane@example.com"))
+	assert.NoError(t, s.DeleteTeam("payments-platform"))
+	_, err = jane.CreateTeam(&model.Team{Name: "orders-platform"})
+	assert.NoError(t, err)
+
+	log, err := s.FetchAudit("", "payments-platform", "", "", "", "", "")
+	assert.NoError(t, err)
+	var actions, actors []string
+	for _, e := range log.Events {
+		actions = append(actions, e.Action)
+		actors = append(actors, e.Actor)
+		assert.Empty(t, e.Service)
+	}
+	assert.Equal(t, []string{model.AuditCreateTeam, model.AuditUpdateTeam, model.AuditAddOwner, model.AuditRemoveOwner, model.AuditDeleteTeam}, actions)
+	assert.Equal(t, []string{"jane.doe", "jane.doe", "jane.doe", "jane.doe", model.AnonymousActor}, actors)
+	assert.Nil(t, log.Events[0].Before)
+	assert.Contains(t, string(log.Events[3].Before), `"jane@example.com"`)
+	assert.Nil(t, log.Events[4].After)
+}
+
+func TestTeamChangesShouldBeRolledBackWithTheTransaction(t *testing.T) {
+	s := newTestService()
+	_, _ = s.CreateTeam(&model.Team{Name: "payments-platform"})
+
+	err := s.transaction(func(tx *Service) error {
+		if err := tx.teams.Add(&model.Team{Name: "orders-platform"}); err != nil {
+			return err
+		}
+		if err := tx.teams.Delete("payments-platform"); err != nil {
+			return err
+		}
+		return errors.New("audit failed")
+	})
+	assert.EqualError(t, err, "audit failed")
+
+	teams, _ := s.FetchTeams()
+	assert.Len(t, teams, 1)
+	assert.Equal(t, "payments-platform", teams[0].Name)
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/docker/go-connections/nat"
 	"github.com/gin-gonic/gin"
@@ -10,6 +11,8 @@ import (
 	"github.com/suyog1pathak/services/migration"
 	"github.com/suyog1pathak/services/pkg/config"
 	"github.com/suyog1pathak/services/pkg/datastore"
+	"github.com/suyog1pathak/services/pkg/model"
+	"github.com/suyog1pathak/services/pkg/semver"
 	S "github.com/suyog1pathak/services/pkg/server"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/mysql"
@@ -20,6 +23,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -104,6 +108,16 @@ func makeRequest(method, url, body string) *httptest.ResponseRecorder {
 	return response
 }
 
+// makeRequestAs makes the request on behalf of the user, as the X-User header names it.
+func makeRequestAs(user, method, url, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, TestUrlPrefix+url, bytes.NewBuffer([]byte(body)))
+	request.Header.Set("X-User", user)
+
+	response := httptest.NewRecorder()
+	S.InitRouter().ServeHTTP(response, request)
+	return response
+}
+
 func getRespBodyBytes(writer *httptest.ResponseRecorder) []byte {
 	resp := writer.Result()
 	body, _ := io.ReadAll(resp.Body)
@@ -116,3 +130,131 @@ func TestShouldCheckGetAllServiceResponse(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusOK, response.Code)
 }
+
+func TestShouldAllocateDistinctVersionsOnConcurrentUpdates(t *testing.T) {
+	response := makeRequest("POST", "/services", `{"serviceName":"concurrent","describe":"v1"}`)
+	assert.Equal(t, http.StatusCreated, response.Code)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response := makeRequest("PATCH", "/services/concurrent", `{"describe":"next"}`)
+			assert.Equal(t, http.StatusCreated, response.Code)
+		}()
+	}
+	wg.Wait()
+
+	var versions []model.Service
+	response = makeRequest("GET", "/services/concurrent", "")
+	assert.NoError(t, json.Unmarshal(getRespBodyBytes(response), &versions))
+	seen := map[string]bool{}
+	for _, v := range versions {
+		seen[v.Version] = true
+	}
+	assert.Len(t, seen, 6)
+	for v := 1; v <= 6; v++ {
+		assert.True(t, seen[fmt.Sprintf("%d.0.0", v)], "version %d.0.0 is missing", v)
+	}
+}
+
+func TestShouldNotCloseADependencyCycleConcurrently(t *testing.T) {
+	for _, name := range []string{"cycle-a", "cycle-b"} {
+		response := makeRequest("POST", "/services", `{"serviceName":"`+name+`"}`)
+		assert.Equal(t, http.StatusCreated, response.Code)
+	}
+
+	// each version is valid on its own, only one of them may be published.
+	var wg sync.WaitGroup
+	codes := make([]int, 2)
+	for i, pair := range [][]string{{"cycle-a", "cycle-b"}, {"cycle-b", "cycle-a"}} {
+		wg.Add(1)
+		go func(i int, name, dependency string) {
+			defer wg.Done()
+			codes[i] = makeRequest("PATCH", "/services/"+name, `{"dependencies":[{"serviceName":"`+dependency+`"}]}`).Code
+		}(i, pair[0], pair[1])
+	}
+	wg.Wait()
+	assert.ElementsMatch(t, []int{http.StatusCreated, http.StatusConflict}, codes)
+}
+
+func TestShouldAuditEveryVersionPublishedConcurrently(t *testing.T) {
+	response := makeRequestAs("deployer", "POST", "/services", `{"serviceName":"concurrent-audited"}`)
+	assert.Equal(t, http.StatusCreated, response.Code)
+
+	// the versions are published in the transactions of their audit events, a deadlock between them is retried
+	// as a whole, so every request succeeds and is audited once.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(bump string) {
+			defer wg.Done()
+			response := makeRequestAs("deployer", "PATCH", "/services/concurrent-audited", `{"bump":"`+bump+`"}`)
+			assert.Equal(t, http.StatusCreated, response.Code, string(getRespBodyBytes(response)))
+		}([]string{"major", "minor", "patch"}[i%3])
+	}
+	wg.Wait()
+
+	var versions []model.Service
+	response = makeRequest("GET", "/services/concurrent-audited", "")
+	assert.NoError(t, json.Unmarshal(getRespBodyBytes(response), &versions))
+	seen := map[string]bool{}
+	for _, v := range versions {
+		seen[v.Version] = true
+	}
+	assert.Len(t, seen, 11)
+
+	var audit struct {
+		Events []model.AuditEvent `json:"events"`
+	}
+	response = makeRequest("GET", "/audit?service=concurrent-audited&actor=deployer", "")
+	assert.NoError(t, json.Unmarshal(getRespBodyBytes(response), &audit))
+	published := map[string]int{}
+	for _, e := range audit.Events {
+		if e.Action == model.AuditCreateVersion {
+			published[e.Version]++
+		}
+	}
+	assert.Len(t, published, 10)
+	for version, events := range published {
+		assert.Equal(t, 1, events, version)
+	}
+}
+
+// BenchmarkShouldListServicesInConstantQueries lists pages of growing size, the page is fetched with a fixed
+// number of queries so the time per request should barely grow with the page size.
+//
+//	go test -run '^$' -bench BenchmarkShouldListServices .
+func BenchmarkShouldListServicesInConstantQueries(b *testing.B) {
+	db, err := datastore.GetDBConnection()
+	if err != nil {
+		b.Fatalf("Failed to create db connection: %v", err)
+	}
+	repo := model.NewGormServiceRepository(db)
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprintf("bench-%03d", i)
+		if _, err := repo.GetByName(name); err == nil {
+			continue
+		}
+		for _, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+			s := &model.Service{Name: name, Description: "benchmark", IsActive: true}
+			s.SetVersion(semver.MustParse(version))
+			if err := repo.Add(s); err != nil {
+				b.Fatalf("Failed to seed services: %v", err)
+			}
+		}
+	}
+
+	for _, pageSize := range []int{10, 50, 100} {
+		b.Run(fmt.Sprintf("pagesize=%d", pageSize), func(b *testing.B) {
+			url := fmt.Sprintf("/services?query=bench-&match=prefix&sort=name&dir=asc&pagesize=%d", pageSize)
+			for i := 0; i < b.N; i++ {
+				response := makeRequest("GET", url, "")
+				if response.Code != http.StatusOK {
+					b.Fatalf("unexpected status %d", response.Code)
+				}
+			}
+		})
+	}
+}
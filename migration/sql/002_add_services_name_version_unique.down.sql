@@ -0,0 +1,3 @@
+ALTER TABLE `services`
+    DROP INDEX `idx_services_name_version`,
+    DROP COLUMN `live`;
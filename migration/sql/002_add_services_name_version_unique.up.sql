@@ -0,0 +1,5 @@
+-- `live` is 1 for live rows and NULL for soft deleted ones. NULLs never collide in a unique index,
+-- so (name, version) stays unique among live rows while a deleted name can still be created again.
+ALTER TABLE `services`
+    ADD COLUMN `live` TINYINT AS (IF(`deleted_at` IS NULL, 1, NULL)) STORED,
+    ADD UNIQUE INDEX `idx_services_name_version` (`name`, `version`, `live`);
@@ -0,0 +1,10 @@
+ALTER TABLE `services`
+    DROP INDEX `idx_services_name_version_key`,
+    DROP INDEX `idx_services_name_version`,
+    DROP COLUMN `version_key`,
+    DROP COLUMN `prerelease`,
+    DROP COLUMN `patch`,
+    DROP COLUMN `minor`,
+    DROP COLUMN `major`,
+    MODIFY COLUMN `version` INT DEFAULT 1,
+    ADD UNIQUE INDEX `idx_services_name_version` (`name`, `version`, `live`);
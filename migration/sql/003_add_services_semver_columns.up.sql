@@ -0,0 +1,12 @@
+-- versions become semantic versions, `version` holds the canonical string (e.g. 1.4.0, 2.0.0-rc.1).
+-- `version_key` is a byte-ordered encoding of the precedence so versions can be sorted and compared in SQL.
+ALTER TABLE `services`
+    DROP INDEX `idx_services_name_version`,
+    MODIFY COLUMN `version` VARCHAR(64) DEFAULT NULL,
+    ADD COLUMN `major` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `version`,
+    ADD COLUMN `minor` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `major`,
+    ADD COLUMN `patch` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `minor`,
+    ADD COLUMN `prerelease` VARCHAR(64) NOT NULL DEFAULT '' AFTER `patch`,
+    ADD COLUMN `version_key` VARBINARY(255) DEFAULT NULL AFTER `prerelease`,
+    ADD UNIQUE INDEX `idx_services_name_version` (`name`, `version`, `live`),
+    ADD INDEX `idx_services_name_version_key` (`name`, `version_key`);
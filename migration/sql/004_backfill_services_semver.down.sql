@@ -0,0 +1,9 @@
+-- only versions N.0.0 have an integer equivalent. a version with a minor, patch or prerelease part would be lost and
+-- collide with N.0.0 on the unique index migration 003 puts back, so the rollback is refused instead: the string
+-- assigned to the integer `major` fails the whole statement in strict mode, the MySQL default, before any row changes.
+-- delete or purge those versions first to roll back.
+UPDATE `services`
+SET `major`       = IF(`minor` = 0 AND `patch` = 0 AND `prerelease` = '', `major`,
+                       'versions other than N.0.0 have no integer equivalent, delete them to roll back'),
+    `version`     = `major`,
+    `version_key` = NULL;
@@ -0,0 +1,7 @@
+-- existing integer versions N become N.0.0, the key matches semver.Version.Key() for releases.
+-- assignments are applied left to right, `major` is set first and the other columns are derived from it.
+UPDATE `services`
+SET `major`       = CAST(`version` AS UNSIGNED),
+    `version`     = CONCAT(`major`, '.0.0'),
+    `version_key` = CONCAT(LPAD(`major`, 10, '0'), '.0000000000.0000000000~')
+WHERE `version_key` IS NULL;
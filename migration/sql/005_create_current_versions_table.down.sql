@@ -0,0 +1 @@
+DROP TABLE `current_versions`;
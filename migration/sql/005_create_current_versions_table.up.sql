@@ -0,0 +1,11 @@
+-- points at the version of each service served as current, kept apart from the version rows
+-- so a service can be promoted to any version and rolled back.
+CREATE TABLE `current_versions`
+(
+    `name`        varchar(50)    NOT NULL,
+    `version`     varchar(64)    NOT NULL,
+    `version_key` varbinary(255) NOT NULL,
+    `created_at`  datetime(3) DEFAULT NULL,
+    `updated_at`  datetime(3) DEFAULT NULL,
+    PRIMARY KEY (`name`)
+);
@@ -0,0 +1 @@
+DELETE FROM `current_versions`;
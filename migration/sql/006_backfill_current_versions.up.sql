@@ -0,0 +1,9 @@
+-- the latest live version of every service becomes its current version.
+INSERT INTO `current_versions` (`name`, `version`, `version_key`, `created_at`, `updated_at`)
+SELECT s.`name`, s.`version`, s.`version_key`, NOW(3), NOW(3)
+FROM `services` s
+         JOIN (SELECT `name`, MAX(`version_key`) AS `version_key`
+               FROM `services`
+               WHERE `deleted_at` IS NULL
+               GROUP BY `name`) latest ON latest.`name` = s.`name` AND latest.`version_key` = s.`version_key`
+WHERE s.`deleted_at` IS NULL;
@@ -0,0 +1,11 @@
+-- teams own services, a service references its team by name.
+CREATE TABLE `teams`
+(
+    `id`          bigint unsigned NOT NULL AUTO_INCREMENT,
+    `created_at`  datetime(3) DEFAULT NULL,
+    `updated_at`  datetime(3) DEFAULT NULL,
+    `name`        varchar(50) NOT NULL,
+    `description` LONGTEXT DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_teams_name` (`name`)
+);
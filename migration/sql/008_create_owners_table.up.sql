@@ -0,0 +1,13 @@
+-- owners are the people responsible for the services of a team, they go with the team.
+CREATE TABLE `owners`
+(
+    `id`         bigint unsigned NOT NULL AUTO_INCREMENT,
+    `created_at` datetime(3) DEFAULT NULL,
+    `updated_at` datetime(3) DEFAULT NULL,
+    `team_id`    bigint unsigned NOT NULL,
+    `name`       varchar(100) DEFAULT NULL,
+    `email`      varchar(255) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_owners_team_email` (`team_id`, `email`),
+    CONSTRAINT `fk_owners_team` FOREIGN KEY (`team_id`) REFERENCES `teams` (`id`) ON DELETE CASCADE
+);
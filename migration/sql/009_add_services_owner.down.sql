@@ -0,0 +1,2 @@
+ALTER TABLE `services`
+    DROP COLUMN `owner`;
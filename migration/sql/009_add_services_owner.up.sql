@@ -0,0 +1,2 @@
+ALTER TABLE `services`
+    ADD COLUMN `owner` varchar(50) DEFAULT NULL AFTER `tags`;
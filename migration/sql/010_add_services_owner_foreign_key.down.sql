@@ -0,0 +1,2 @@
+ALTER TABLE `services`
+    DROP FOREIGN KEY `fk_services_owner`;
@@ -0,0 +1,3 @@
+-- deleting a team leaves the versions it owned, soft deleted ones included, without an owner.
+ALTER TABLE `services`
+    ADD CONSTRAINT `fk_services_owner` FOREIGN KEY (`owner`) REFERENCES `teams` (`name`) ON DELETE SET NULL;
@@ -0,0 +1,14 @@
+-- links a service version to the services it depends on, by name as a service has many version rows.
+-- the dependencies go with the version row when it is purged.
+CREATE TABLE `service_dependencies`
+(
+    `id`                 bigint unsigned NOT NULL AUTO_INCREMENT,
+    `created_at`         datetime(3) DEFAULT NULL,
+    `service_id`         bigint unsigned NOT NULL,
+    `name`               varchar(50)  NOT NULL,
+    `version_constraint` varchar(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_service_dependencies_service_name` (`service_id`, `name`),
+    INDEX `idx_service_dependencies_name` (`name`),
+    CONSTRAINT `fk_service_dependencies_service` FOREIGN KEY (`service_id`) REFERENCES `services` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+-- key=value labels of a service version, they replace the comma separated tags which are now derived from them.
+-- keys and values are compared case sensitively and are wider than the api allows to hold the migrated tags.
+CREATE TABLE `service_labels`
+(
+    `id`         bigint unsigned NOT NULL AUTO_INCREMENT,
+    `created_at` datetime(3) DEFAULT NULL,
+    `service_id` bigint unsigned NOT NULL,
+    `key`        varchar(255) COLLATE utf8mb4_bin NOT NULL,
+    `value`      varchar(255) COLLATE utf8mb4_bin NOT NULL DEFAULT '',
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_service_labels_service_key` (`service_id`, `key`),
+    INDEX `idx_service_labels_key_value` (`key`, `value`),
+    CONSTRAINT `fk_service_labels_service` FOREIGN KEY (`service_id`) REFERENCES `services` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+DELETE FROM `service_labels`;
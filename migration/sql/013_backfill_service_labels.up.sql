@@ -0,0 +1,32 @@
+-- every comma separated tag becomes a label, key=value tags are split on the first = and the others become
+-- labels without a value. a tag repeated within a version, or a label already there, is stored once. a tag whose
+-- key or value does not follow the label syntax the api validates is left out, it is kept in legacy_tags by
+-- migration 015.
+INSERT IGNORE INTO `service_labels` (`created_at`, `service_id`, `key`, `value`)
+WITH RECURSIVE `split` (`service_id`, `tag`, `rest`) AS (
+    SELECT `id`,
+           SUBSTRING_INDEX(`tags`, ',', 1),
+           IF(LOCATE(',', `tags`) > 0, SUBSTRING(`tags`, LOCATE(',', `tags`) + 1), NULL)
+    FROM `services`
+    WHERE `tags` IS NOT NULL
+      AND `tags` <> ''
+    UNION ALL
+    SELECT `service_id`,
+           SUBSTRING_INDEX(`rest`, ',', 1),
+           IF(LOCATE(',', `rest`) > 0, SUBSTRING(`rest`, LOCATE(',', `rest`) + 1), NULL)
+    FROM `split`
+    WHERE `rest` IS NOT NULL
+),
+`label` (`service_id`, `key`, `value`) AS (
+    SELECT `service_id`,
+           TRIM(SUBSTRING_INDEX(`tag`, '=', 1)),
+           IF(LOCATE('=', `tag`) > 0, TRIM(SUBSTRING(`tag`, LOCATE('=', `tag`) + 1)), '')
+    FROM `split`
+)
+SELECT NOW(3),
+       `service_id`,
+       `key`,
+       `value`
+FROM `label`
+WHERE REGEXP_LIKE(`key`, '^([a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?/)?[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$', 'c')
+  AND REGEXP_LIKE(`value`, '^([A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?)?$', 'c');
@@ -0,0 +1,3 @@
+ALTER TABLE `services`
+    DROP COLUMN `legacy_tags`,
+    MODIFY COLUMN `tags` varchar(255) DEFAULT NULL;
@@ -0,0 +1,5 @@
+-- the tags rendered from the labels may not fit the 255 characters clients used to write. the tags clients wrote
+-- are kept in legacy_tags, including those the labels could not take.
+ALTER TABLE `services`
+    MODIFY COLUMN `tags` TEXT DEFAULT NULL,
+    ADD COLUMN `legacy_tags` varchar(255) DEFAULT NULL AFTER `tags`;
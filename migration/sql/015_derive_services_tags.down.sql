@@ -0,0 +1,5 @@
+-- the versions which had tags before get them back as clients wrote them, the versions created since keep the tags
+-- rendered from their labels, cut to the width of the column they go back to.
+UPDATE `services`
+SET `tags`        = IF(`legacy_tags` IS NULL, LEFT(`tags`, 255), `legacy_tags`),
+    `legacy_tags` = NULL;
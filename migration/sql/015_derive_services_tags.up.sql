@@ -0,0 +1,7 @@
+-- tags are rendered from the labels the way the api does, ordered by key with key=value or the key alone. the tags
+-- as clients wrote them are copied to legacy_tags first, the assignments of an update are made from left to right.
+UPDATE `services` s
+SET s.`legacy_tags` = s.`tags`,
+    s.`tags`        = (SELECT GROUP_CONCAT(IF(l.`value` = '', l.`key`, CONCAT(l.`key`, '=', l.`value`)) ORDER BY l.`key` SEPARATOR ',')
+                       FROM `service_labels` l
+                       WHERE l.`service_id` = s.`id`);
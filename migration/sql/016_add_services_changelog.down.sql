@@ -0,0 +1,3 @@
+ALTER TABLE `services`
+    DROP COLUMN `author`,
+    DROP COLUMN `changelog`;
@@ -0,0 +1,4 @@
+-- the release notes of a version and who published it, versions published before have neither.
+ALTER TABLE `services`
+    ADD COLUMN `changelog` TEXT DEFAULT NULL AFTER `owner`,
+    ADD COLUMN `author` varchar(255) DEFAULT NULL AFTER `changelog`;
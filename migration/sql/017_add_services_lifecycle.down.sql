@@ -0,0 +1,4 @@
+ALTER TABLE `services`
+    DROP INDEX `idx_services_lifecycle`,
+    DROP COLUMN `sunset_date`,
+    DROP COLUMN `lifecycle`;
@@ -0,0 +1,5 @@
+-- is_active is kept for old clients, it is derived from the lifecycle from now on.
+ALTER TABLE `services`
+    ADD COLUMN `lifecycle` varchar(16) NOT NULL DEFAULT 'production' AFTER `is_active`,
+    ADD COLUMN `sunset_date` datetime(3) DEFAULT NULL AFTER `lifecycle`,
+    ADD INDEX `idx_services_lifecycle` (`lifecycle`);
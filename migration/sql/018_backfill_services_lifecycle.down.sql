@@ -0,0 +1,2 @@
+UPDATE `services`
+SET `lifecycle` = 'production';
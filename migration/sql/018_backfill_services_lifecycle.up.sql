@@ -0,0 +1,7 @@
+-- active prereleases are experimental, inactive versions are deprecated so they can still be brought back into production.
+UPDATE `services`
+SET `lifecycle` = CASE
+        WHEN NOT `is_active` THEN 'deprecated'
+        WHEN `prerelease` <> '' THEN 'experimental'
+        ELSE 'production'
+    END;
@@ -0,0 +1,4 @@
+ALTER TABLE `services`
+    DROP INDEX `idx_services_lifecycle_sunset_date`,
+    DROP COLUMN `successor`,
+    DROP COLUMN `deprecated_at`;
@@ -0,0 +1,5 @@
+-- the deprecation of a version, sent to the clients reading it in the Deprecation, Sunset and Link headers.
+ALTER TABLE `services`
+    ADD COLUMN `deprecated_at` datetime(3) DEFAULT NULL AFTER `lifecycle`,
+    ADD COLUMN `successor` varchar(50) DEFAULT NULL AFTER `sunset_date`,
+    ADD INDEX `idx_services_lifecycle_sunset_date` (`lifecycle`, `sunset_date`);
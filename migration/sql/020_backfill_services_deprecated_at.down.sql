@@ -0,0 +1,2 @@
+UPDATE `services`
+SET `deprecated_at` = NULL;
@@ -0,0 +1,4 @@
+-- the versions deprecated by the lifecycle backfill were last changed when they were deactivated.
+UPDATE `services`
+SET `deprecated_at` = `updated_at`
+WHERE `lifecycle` IN ('deprecated', 'retired');
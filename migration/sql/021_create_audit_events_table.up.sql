@@ -0,0 +1,17 @@
+-- append-only log of the changes made to services, it has no foreign key so it outlives purged services.
+CREATE TABLE `audit_events`
+(
+    `id`         bigint unsigned NOT NULL AUTO_INCREMENT,
+    `created_at` datetime(3) DEFAULT NULL,
+    `actor`      varchar(255) NOT NULL,
+    `action`     varchar(32)  NOT NULL,
+    `service`    varchar(50)  NOT NULL,
+    `version`    varchar(64)  DEFAULT NULL,
+    `before`     JSON         DEFAULT NULL,
+    `after`      JSON         DEFAULT NULL,
+    `request_id` varchar(64)  DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_audit_events_service` (`service`),
+    INDEX `idx_audit_events_actor` (`actor`),
+    INDEX `idx_audit_events_created_at` (`created_at`)
+);
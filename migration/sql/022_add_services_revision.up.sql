@@ -0,0 +1,3 @@
+-- revision counter of a version, bumped by every update and served as its ETag for optimistic concurrency.
+ALTER TABLE `services`
+    ADD COLUMN `revision` int unsigned NOT NULL DEFAULT 1 AFTER `version_key`;
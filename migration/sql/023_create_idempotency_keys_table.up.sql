@@ -0,0 +1,15 @@
+-- responses of the requests made with an Idempotency-Key header, replayed to their retries until they expire.
+CREATE TABLE `idempotency_keys`
+(
+    `id`              bigint unsigned   NOT NULL AUTO_INCREMENT,
+    `created_at`      datetime(3)       DEFAULT NULL,
+    `expires_at`      datetime(3)       NOT NULL,
+    `idempotency_key` varchar(255)      NOT NULL,
+    `fingerprint`     char(64)          NOT NULL,
+    `status_code`     smallint unsigned NOT NULL DEFAULT 0,
+    `header`          JSON              DEFAULT NULL,
+    `body`            mediumblob        DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_idempotency_keys_key` (`idempotency_key`),
+    INDEX `idx_idempotency_keys_expires_at` (`expires_at`)
+);
@@ -0,0 +1,4 @@
+-- changes made to teams are audited with the name of the team and an empty service.
+ALTER TABLE `audit_events`
+    ADD COLUMN `team` varchar(50) DEFAULT NULL AFTER `service`,
+    ADD INDEX `idx_audit_events_team` (`team`);
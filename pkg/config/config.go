@@ -26,6 +26,14 @@ type App struct {
 	ListeningPort  int           `mapstructure:"http_port"`
 	DrainingPeriod time.Duration `mapstructure:"draining_period"`
 	LogLevel       string        `mapstructure:"log_level"`
+	Datastore      string        `mapstructure:"datastore"`
+	PurgeAfter     time.Duration `mapstructure:"purge_after"`
+	PurgeInterval  time.Duration `mapstructure:"purge_interval"`
+	PurgeBatchSize int           `mapstructure:"purge_batch_size"`
+	CursorSecret   string        `mapstructure:"cursor_secret"`
+	IdempotencyTTL time.Duration `mapstructure:"idempotency_ttl"`
+	// IdempotencyLease is how long a request in progress holds its key, a retry takes the key over after it.
+	IdempotencyLease time.Duration `mapstructure:"idempotency_lease"`
 }
 
 type Config struct {
@@ -46,6 +54,11 @@ func CreateConfig() (Config, error) {
 	viper.SetDefault("log_level", "INFO")
 	viper.SetDefault("http_port", 8080)
 	viper.SetDefault("draining_period", 30)
+	viper.SetDefault("app.datastore", "mysql")
+	viper.SetDefault("app.purge_interval", "1h")
+	viper.SetDefault("app.purge_batch_size", 500)
+	viper.SetDefault("app.idempotency_ttl", "24h")
+	viper.SetDefault("app.idempotency_lease", "1m")
 
 	// Read the config file
 	err := viper.ReadInConfig() // Find and read the config file
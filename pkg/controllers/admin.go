@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	sloggin "github.com/samber/slog-gin"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	"github.com/suyog1pathak/services/internal/purge"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"net/http"
+)
+
+type AdminController struct {
+	purger *purge.Purger
+}
+
+func NewAdminController(purger *purge.Purger) *AdminController {
+	return &AdminController{purger: purger}
+}
+
+// PurgeServices
+//
+//	@BasePath		/api/v1/
+//	@Summary		purge deleted services
+//	@Description	hard delete the service versions soft deleted for longer than the configured retention window
+//	@Tags			admin
+//	@Accept			json
+//	@Param			dry_run	query	bool	false	"only report the service versions which would be purged"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
+//	@Produce		application/json
+//	@Success		200	{object}	apiv1.PurgeReport
+//	@Failure		409	{object}	GenericErrorResponse
+//	@Failure		500	{object}	GenericErrorResponse
+//	@Router			/api/v1/admin/purge [post]
+func (ac *AdminController) PurgeServices(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+	log.Info("received a request to purge deleted services.", "dryRun", dryRun)
+	var report apiv1.PurgeReport
+	report, err := ac.purger.As(c.GetHeader(headerUser), sloggin.GetRequestID(c)).Run(dryRun)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, report)
+}
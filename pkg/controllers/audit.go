@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"net/http"
+)
+
+// GetAudit
+//
+//	@BasePath		/api/v1/
+//	@Summary		Audit log
+//	@Description	changes made to services and teams with who made them and their before and after JSON, in the order they were made.
+//	@Description	the actor is the X-User header of the request as it was sent, the api does not authenticate it: it can only
+//	@Description	be trusted when a proxy authenticating the callers sets the header and strips the one sent by clients.
+//	@Tags			services
+//	@Param			service		query	string	false	"service name"	example(payments)
+//	@Param			team		query	string	false	"team name"	example(payments-platform)
+//	@Param			actor		query	string	false	"who made the changes, the self-asserted X-User header of their requests"	example(jane.doe)
+//	@Param			from		query	string	false	"RFC3339 time the changes are made at or after"	example(2026-01-01T00:00:00Z)
+//	@Param			to			query	string	false	"RFC3339 time the changes are made before"	example(2026-02-01T00:00:00Z)
+//	@Param			since_id	query	int		false	"id of the last event of the previous page"	minimum(0)
+//	@Param			limit		query	int		false	"events per page"	minimum(1)	maximum(1000)	default(100)
+//	@Produce		application/json
+//	@Success		200	{object}	AuditLog
+//	@Failure		400	{object}	GenericErrorResponse
+//	@Failure		500	{object}	GenericErrorResponse
+//	@Router			/api/v1/audit [get]
+func (sc *ServiceController) GetAudit(c *gin.Context) {
+	name, team, actor := c.Query("service"), c.Query("team"), c.Query("actor")
+	log.Info("received a request to get the audit log.", "service", name, "team", team, "actor", actor)
+	response, err := sc.service.FetchAudit(name, team, actor, c.Query("from"), c.Query("to"), c.Query("since_id"), c.Query("limit"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"github.com/suyog1pathak/services/pkg/model"
+	"net/http"
+	"net/url"
+)
+
+// setDeprecationHeaders tells the client the version it reads is going away, with the Deprecation header
+// (RFC 9745), the Sunset header (RFC 8594) and a Link to the service succeeding it.
+func setDeprecationHeaders(c *gin.Context, v model.Service) {
+	if model.IsActiveLifecycle(v.Lifecycle) {
+		return
+	}
+	if v.DeprecatedAt != nil {
+		c.Header("Deprecation", fmt.Sprintf("@%d", v.DeprecatedAt.Unix()))
+	} else {
+		c.Header("Deprecation", "true")
+	}
+	if v.SunsetDate != nil {
+		c.Header("Sunset", v.SunsetDate.UTC().Format(http.TimeFormat))
+	}
+	if v.Successor != "" {
+		c.Header("Link", fmt.Sprintf(`</api/v1/services/%s>; rel="successor-version"`, url.PathEscape(v.Successor)))
+	}
+}
+
+// GetDeprecations
+//
+//	@BasePath		/api/v1/
+//	@Summary		Deprecation report
+//	@Description	deprecated service versions sunsetting within the given number of days, along with the ones past their sunset date which are not retired yet, ordered by sunset date
+//	@Tags			services
+//	@Param			days	query	int	false	"days ahead"	minimum(0)	maximum(3650)	default(30)
+//	@Produce		application/json
+//	@Success		200	{object}	DeprecationReport
+//	@Failure		400	{object}	GenericErrorResponse
+//	@Failure		500	{object}	GenericErrorResponse
+//	@Router			/api/v1/deprecations [get]
+func (sc *ServiceController) GetDeprecations(c *gin.Context) {
+	days := c.Query("days")
+	log.Info("received a request to report deprecations.", "days", days)
+	report, err := sc.service.FetchDeprecations(days)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, report)
+}
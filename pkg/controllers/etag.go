@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/suyog1pathak/services/pkg/util"
+	"strings"
+)
+
+// etag is the entity tag of a service version, its revision.
+func etag(revision int) string {
+	return fmt.Sprintf(`"%d"`, revision)
+}
+
+// ifMatch returns the revisions listed in an If-Match header, nil when any revision matches.
+// If-Match compares strongly, weak and unknown tags never match and are left out.
+func ifMatch(header string) []int {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil
+	}
+	revisions := []int{}
+	for _, tag := range strings.Split(header, ",") {
+		if revision, ok := parseETag(strings.TrimSpace(tag)); ok {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions
+}
+
+// noneMatch reports whether an If-None-Match header lists the revision, it compares weakly.
+func noneMatch(header string, revision int) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if r, ok := parseETag(tag); ok && r == revision {
+			return true
+		}
+	}
+	return false
+}
+
+func parseETag(tag string) (int, bool) {
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, false
+	}
+	revision, err := util.StringToInt(tag[1 : len(tag)-1])
+	return revision, err == nil && revision > 0
+}
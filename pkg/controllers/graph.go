@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	apiv1 "github.com/suyog1pathak/services/api/v1/response"
+	"github.com/suyog1pathak/services/internal/service"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"net/http"
+)
+
+// media types of the graph renderings, the first one is served when the client accepts anything.
+const (
+	mimeJGF     = "application/vnd.jgf+json"
+	mimeJSON    = "application/json"
+	mimeDOT     = "text/vnd.graphviz"
+	mimeMermaid = "text/vnd.mermaid"
+)
+
+// graphFormats maps the format query param to the media type it stands for.
+var graphFormats = map[string]string{
+	"jgf":     mimeJGF,
+	"json":    mimeJGF,
+	"dot":     mimeDOT,
+	"mermaid": mimeMermaid,
+}
+
+// GraphController serves the dependency graph of the catalog.
+type GraphController struct {
+	service *service.Service
+}
+
+func NewGraphController(service *service.Service) *GraphController {
+	return &GraphController{service: service}
+}
+
+// GetGraph
+//
+//	@BasePath		/api/v1/
+//	@Summary		dependency graph
+//	@Description	render the dependency graph of the current versions of the catalog, or of the dependencies or dependents of a service, as JSON Graph Format, Graphviz DOT or a Mermaid flowchart picked by the format param or else the Accept header
+//	@Tags			services
+//	@Param			root		query	string	false	"service the graph is rooted at, the whole catalog by default"
+//	@Param			direction	query	string	false	"edges followed from the root"	Enums(dependencies, dependents)	default(dependencies)
+//	@Param			depth		query	int		false	"levels followed from the root"	minimum(1)	maximum(32)	default(1)
+//	@Param			format		query	string	false	"rendering, overrides the Accept header"	Enums(jgf, dot, mermaid)
+//	@Produce		application/vnd.jgf+json,application/json,text/vnd.graphviz,text/vnd.mermaid
+//	@Success		200	{object}	apiv1.JSONGraph
+//	@Failure		400	{object}	GenericErrorResponse
+//	@Failure		404	{object}	GenericErrorResponse
+//	@Failure		406	{object}	GenericErrorResponse
+//	@Failure		500	{object}	GenericErrorResponse
+//	@Router			/api/v1/graph [get]
+func (gc *GraphController) GetGraph(c *gin.Context) {
+	root := c.Query("root")
+	format := c.Query("format")
+	log.Info("received a request to render the dependency graph.", "root", root, "format", format)
+	mime := c.NegotiateFormat(mimeJGF, mimeJSON, mimeDOT, mimeMermaid)
+	if format != "" {
+		mime = graphFormats[format]
+	}
+	if mime == "" {
+		c.Error(errors.New(customerrors.ErrInvalidGraphFormat))
+		return
+	}
+	graph, err := gc.service.FetchGraph(root, c.Query("direction"), c.Query("depth"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	switch mime {
+	case mimeDOT:
+		c.Data(http.StatusOK, mimeDOT+"; charset=utf-8", []byte(graph.DOT()))
+	case mimeMermaid:
+		c.Data(http.StatusOK, mimeMermaid+"; charset=utf-8", []byte(graph.Mermaid()))
+	default:
+		var jgf apiv1.JSONGraph = graph.JGF()
+		c.Header("Content-Type", mime+"; charset=utf-8")
+		c.IndentedJSON(http.StatusOK, jgf)
+	}
+}
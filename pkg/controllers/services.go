@@ -1,17 +1,43 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	sloggin "github.com/samber/slog-gin"
 	"github.com/suyog1pathak/services/api/v1/generic"
 	apiv1 "github.com/suyog1pathak/services/api/v1/response"
 	"github.com/suyog1pathak/services/internal/service"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
 	log "github.com/suyog1pathak/services/pkg/logger"
 	"github.com/suyog1pathak/services/pkg/model"
 	"github.com/suyog1pathak/services/pkg/util"
 	"net/http"
 )
 
+// headerUser names the caller. It is not authenticated here, whoever reaches the api can send any name, so it is
+// only trustworthy behind a proxy which authenticates requests and sets it.
+const headerUser = "X-User"
+
+// ServiceController serves the /api/v1/services endpoints.
+type ServiceController struct {
+	service *service.Service
+}
+
+func NewServiceController(service *service.Service) *ServiceController {
+	return &ServiceController{service: service}
+}
+
+// as returns the service acting for the caller of the request, the changes it makes are audited as theirs.
+func (sc *ServiceController) as(c *gin.Context) *service.Service {
+	return sc.service.As(caller(c))
+}
+
+// caller is who makes the request, the X-User header is recorded in the audit log as it is sent.
+func caller(c *gin.Context) service.Caller {
+	return service.Caller{Actor: c.GetHeader(headerUser), RequestID: sloggin.GetRequestID(c)}
+}
+
 // CreateService
 //
 //	@BasePath		/api/v1/
@@ -20,18 +46,23 @@ import (
 //	@Tags			services
 //	@Accept			json
 //	@Param			create	service	body	model.Service	true	"Add Service"
+//	@Param			X-User	header	string	false			"author of the version, recorded as sent, it is not authenticated"
+//	@Param			Idempotency-Key	header	string	false	"unique key of the request for its path and X-User, a retry with the same key gets the stored response instead of repeating the request"
 //	@Produce		application/json
 //	@Success		201	{object}	apiv1.Service{}
 //	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		409	{object}	generic.ErrorResponse
+//	@Failure		422	{object}	generic.ErrorResponse
 //	@Failure		500	{object}	generic.ErrorResponse
 //	@Router			/api/v1/services [post]
-func CreateService(c *gin.Context) {
+func (sc *ServiceController) CreateService(c *gin.Context) {
 	_ = apiv1.Service{}
 	reqBodyPtr, _ := c.Get("requestBody")
 	//:TODO
 	reqBody, _ := reqBodyPtr.(*model.Service)
+	reqBody.Author = c.GetHeader(headerUser)
 	log.Info("received a request to create a service.", "body", util.StructToJson(reqBody))
-	response, err := service.Create(reqBody)
+	response, err := sc.as(c).Create(reqBody)
 	if err != nil {
 		c.Error(err)
 		return
@@ -44,21 +75,28 @@ func CreateService(c *gin.Context) {
 //	@BasePath		/api/v1/
 //	@Summary		update service // create new version
 //	@Description	update service // create new version
+//	@Description	the new version is either set explicitly with `version` or derived from the latest version with `bump` (major, minor or patch), major is bumped by default.
+//	@Description	the release notes of the version are given in `changelog`, the X-User header is recorded as its author.
 //	@Tags			services
 //	@Accept			json
 //	@Param			name	path	string	true			"service name"
 //	@Param			update	service	body	model.Service	true	"update Service"
+//	@Param			X-User	header	string	false			"author of the version, recorded as sent, it is not authenticated"
+//	@Param			Idempotency-Key	header	string	false	"unique key of the request for its path and X-User, a retry with the same key gets the stored response instead of repeating the request"
 //	@Produce		application/json
 //	@Success		201	{object}	apiv1.Service{}
 //	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		409	{object}	generic.ErrorResponse
+//	@Failure		422	{object}	generic.ErrorResponse
 //	@Failure		500	{object}	generic.ErrorResponse
 //	@Router			/api/v1/services/{name} [patch]
-func UpdateService(c *gin.Context) {
+func (sc *ServiceController) UpdateService(c *gin.Context) {
 	reqBodyPtr, _ := c.Get("requestBody")
 	reqBody, _ := reqBodyPtr.(*model.Service)
 	name := c.Param("name")
 	reqBody.Name = name
-	response, err := service.CreateVersion(reqBody)
+	reqBody.Author = c.GetHeader(headerUser)
+	response, err := sc.as(c).CreateVersion(reqBody)
 	log.Info("received a request to create a version for the service.", "body", util.StructToJson(reqBody))
 	if err != nil {
 		c.Error(err)
@@ -71,41 +109,106 @@ func UpdateService(c *gin.Context) {
 //
 //	@BasePath		/api/v1/
 //	@Summary		update service version
-//	@Description	update service version
+//	@Description	update service version, a JSON body updates the fields which are set while a JSON Merge Patch (RFC 7396)
+//	@Description	or a JSON Patch (RFC 6902) of the version writes exactly the members it changes, false, empty and null included
 //	@Tags			services
-//	@Accept			json
+//	@Accept			json,application/merge-patch+json,application/json-patch+json
 //	@Param			name	path	string	true			"service name"
-//	@Param			version	path	string	true			"version"
-//	@Param			update	service	body	model.Service	true	"update Service"
+//	@Param			version	path	string	true			"semantic version, e.g. 1.4.0"
+//	@Param			update	service	body	model.Service	true	"update Service, or a patch of it"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
+//	@Param			If-Match	header	string	false	"ETag of the version as it was read, the update fails with 412 when it was changed since"
 //	@Produce		application/json
 //	@Success		201	{object}	model.Service
+//	@Header			201	{string}	ETag	"revision of the updated version"
 //	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		409	{object}	generic.ErrorResponse
+//	@Failure		412	{object}	generic.ErrorResponse
+//	@Failure		415	{object}	generic.ErrorResponse
 //	@Failure		500	{object}	generic.ErrorResponse
 //	@Router			/api/v1/services/{name}/{version} [patch]
-func UpdateServiceVersion(c *gin.Context) {
-	reqBodyPtr, _ := c.Get("requestBody")
-	reqBody, _ := reqBodyPtr.(*model.Service)
+func (sc *ServiceController) UpdateServiceVersion(c *gin.Context) {
 	name := c.Param("name")
-	log.Info("received a request to update the existing version of the service.", "name", name)
-	versionStr := c.Param("version")
-	version, err := util.StringToInt(versionStr)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, generic.ErrorResponse{
-			Message: "version is not a int",
-			Error:   err.Error(),
-		})
+	version := c.Param("version")
+	log.Info("received a request to update the existing version of the service.", "name", name, "version", version)
+	if patch, ok := c.Get("patch"); ok {
+		response, err := sc.as(c).PatchVersion(name, version, c.GetString("patchType"), patch.([]byte), ifMatch(c.GetHeader("If-Match")))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.Header("ETag", etag(response.Revision))
+		c.IndentedJSON(http.StatusCreated, response)
 		return
 	}
+	reqBodyPtr, _ := c.Get("requestBody")
+	reqBody, _ := reqBodyPtr.(*model.Service)
 	reqBody.Name = name
 	reqBody.Version = version
-	response, err := service.UpdateVersion(reqBody)
+	response, err := sc.as(c).UpdateVersionIfMatch(reqBody, ifMatch(c.GetHeader("If-Match")))
 	if err != nil {
 		c.Error(err)
 		return
 	}
+	c.Header("ETag", etag(response.Revision))
 	c.IndentedJSON(http.StatusCreated, response)
 }
 
+// PromoteServiceVersion
+//
+//	@BasePath		/api/v1/
+//	@Summary		promote service version
+//	@Description	make the version the current version of the service
+//	@Tags			services
+//	@Accept			json
+//	@Param			name	path	string	true	"service name"
+//	@Param			version	path	string	true	"semantic version, e.g. 1.4.0"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
+//	@Produce		application/json
+//	@Success		200	{object}	apiv1.Service{}
+//	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/services/{name}/promote/{version} [post]
+func (sc *ServiceController) PromoteServiceVersion(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+	log.Info("received a request to promote the service version.", "name", name, "version", version)
+	response, err := sc.as(c).Promote(name, version)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// RollbackService
+//
+//	@BasePath		/api/v1/
+//	@Summary		rollback service
+//	@Description	point the service at the highest released version lower than its current version
+//	@Tags			services
+//	@Accept			json
+//	@Param			name	path	string	true	"service name"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
+//	@Produce		application/json
+//	@Success		200	{object}	apiv1.Service{}
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		409	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/services/{name}/rollback [post]
+func (sc *ServiceController) RollbackService(c *gin.Context) {
+	name := c.Param("name")
+	log.Info("received a request to rollback the service.", "name", name)
+	response, err := sc.as(c).Rollback(name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
 // GetServiceByName
 //
 //	@BasePath		/api/v1/
@@ -114,16 +217,221 @@ func UpdateServiceVersion(c *gin.Context) {
 //	@Tags			services
 //	@Accept			json
 //	@Param			name	path	string	true	"service name"
+//	@Param			as_of	query	string	false	"RFC3339 time to describe the service as it was at"	example(2026-01-31T12:00:00Z)
 //	@Produce		application/json
 //	@Success		200	{object}	[]model.Service
+//	@Header			200	{string}	Deprecation	"@<unix time> the current version was deprecated at, when it is deprecated or retired"
+//	@Header			200	{string}	Sunset		"HTTP date the current version is sunset at"
+//	@Header			200	{string}	Link		"successor service of the current version, rel=successor-version"
 //	@Failure		500	{object}	generic.ErrorResponse
 //	@Failure		400	{object}	generic.ErrorResponse
 //	@Failure		404	{object}	generic.ErrorResponse
 //	@Router			/api/v1/services/{name} [get]
-func GetServiceByName(c *gin.Context) {
+func (sc *ServiceController) GetServiceByName(c *gin.Context) {
 	name := c.Param("name")
 	log.Info("received a request to list all existing versions of the service.", "name", name)
-	response, err := service.FetchByName(name)
+	svc, err := sc.service.AsOf(c.Query("as_of"), name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response, err := svc.FetchByName(name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	current, err := svc.FetchCurrent(name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setDeprecationHeaders(c, current)
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// GetServiceVersions
+//
+//	@BasePath		/api/v1/
+//	@Summary		List service versions matching a constraint
+//	@Description	List service versions ordered by semver precedence, optionally filtered by a version constraint
+//	@Tags			services
+//	@Accept			json
+//	@Param			name		path	string	true	"service name"
+//	@Param			constraint	query	string	false	"semver constraint, e.g. ^1.2, ~1.2.3, 1.x or >=1.0 <2"	example(^1.2)
+//	@Produce		application/json
+//	@Success		200	{object}	[]model.Service
+//	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/services/{name}/versions [get]
+func (sc *ServiceController) GetServiceVersions(c *gin.Context) {
+	name := c.Param("name")
+	constraint := c.Query("constraint")
+	log.Info("received a request to list versions of the service.", "name", name, "constraint", constraint)
+	response, err := sc.service.FetchVersions(name, constraint)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// media types of the diff renderings, the whole diff is served when the client accepts anything.
+const (
+	mimeJSONPatch = "application/json-patch+json"
+	mimeDiff      = "text/x-diff"
+	mimeText      = "text/plain"
+)
+
+// diffFormats maps the format query param to the media type it stands for.
+var diffFormats = map[string]string{
+	"json":    mimeJSON,
+	"patch":   mimeJSONPatch,
+	"unified": mimeDiff,
+}
+
+// GetServiceDiff
+//
+//	@BasePath		/api/v1/
+//	@Summary		Diff two service versions
+//	@Description	field level diff between two versions of the service, of the description, state, tags, labels and any other field, as a JSON Patch (RFC 6902), unified text or both, picked by the format param or else the Accept header
+//	@Tags			services
+//	@Param			name	path	string	true	"service name"
+//	@Param			from	query	string	true	"semantic version compared from, partial versions like 2 are read as 2.0.0"	example(1.0.0)
+//	@Param			to		query	string	true	"semantic version compared to"	example(2.0.0)
+//	@Param			format	query	string	false	"rendering, overrides the Accept header"	Enums(json, patch, unified)
+//	@Produce		application/json,application/json-patch+json,text/x-diff,text/plain
+//	@Success		200	{object}	apiv1.VersionDiff
+//	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		406	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/services/{name}/diff [get]
+func (sc *ServiceController) GetServiceDiff(c *gin.Context) {
+	name := c.Param("name")
+	from, to := c.Query("from"), c.Query("to")
+	format := c.Query("format")
+	log.Info("received a request to diff service versions.", "name", name, "from", from, "to", to, "format", format)
+	mime := c.NegotiateFormat(mimeJSON, mimeJSONPatch, mimeDiff, mimeText)
+	if format != "" {
+		mime = diffFormats[format]
+	}
+	if mime == "" {
+		c.Error(errors.New(customerrors.ErrInvalidDiffFormat))
+		return
+	}
+	diff, err := sc.service.FetchDiff(name, from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	switch mime {
+	case mimeJSONPatch:
+		c.Header("Content-Type", mimeJSONPatch+"; charset=utf-8")
+		c.IndentedJSON(http.StatusOK, diff.Patch)
+	case mimeDiff, mimeText:
+		c.Data(http.StatusOK, mime+"; charset=utf-8", []byte(diff.Unified))
+	default:
+		c.IndentedJSON(http.StatusOK, diff)
+	}
+}
+
+// media type of the Markdown rendering of the changelog.
+const mimeMarkdown = "text/markdown"
+
+// changelogFormats maps the format query param to the media type it stands for.
+var changelogFormats = map[string]string{
+	"json":     mimeJSON,
+	"markdown": mimeMarkdown,
+}
+
+// GetServiceChangelog
+//
+//	@BasePath		/api/v1/
+//	@Summary		Service changelog
+//	@Description	release notes of all live versions of the service with their author and publication time, newest first, as JSON or Markdown picked by the format param or else the Accept header
+//	@Tags			services
+//	@Param			name	path	string	true	"service name"
+//	@Param			format	query	string	false	"rendering, overrides the Accept header"	Enums(json, markdown)
+//	@Produce		application/json,text/markdown
+//	@Success		200	{object}	apiv1.Changelog
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		406	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/services/{name}/changelog [get]
+func (sc *ServiceController) GetServiceChangelog(c *gin.Context) {
+	name := c.Param("name")
+	format := c.Query("format")
+	log.Info("received a request to render the changelog of the service.", "name", name, "format", format)
+	mime := c.NegotiateFormat(mimeJSON, mimeMarkdown)
+	if format != "" {
+		mime = changelogFormats[format]
+	}
+	if mime == "" {
+		c.Error(errors.New(customerrors.ErrInvalidChangelogFormat))
+		return
+	}
+	changelog, err := sc.service.FetchChangelog(name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if mime == mimeMarkdown {
+		c.Data(http.StatusOK, mimeMarkdown+"; charset=utf-8", []byte(changelog.Markdown()))
+		return
+	}
+	c.IndentedJSON(http.StatusOK, changelog)
+}
+
+// GetServiceDependencies
+//
+//	@BasePath		/api/v1/
+//	@Summary		List service dependencies
+//	@Description	List the services a version of the service depends on, transitively down to depth levels through the current versions of the dependencies
+//	@Tags			services
+//	@Accept			json
+//	@Param			name	path	string	true	"service name"
+//	@Param			version	query	string	false	"semantic version, the current version by default"
+//	@Param			depth	query	int		false	"levels of dependencies to follow"	minimum(1)	maximum(32)	default(1)
+//	@Produce		application/json
+//	@Success		200	{object}	apiv1.DependencyGraph
+//	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/services/{name}/dependencies [get]
+func (sc *ServiceController) GetServiceDependencies(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Query("version")
+	depth := c.Query("depth")
+	log.Info("received a request to list dependencies of the service.", "name", name, "version", version, "depth", depth)
+	response, err := sc.service.FetchDependencies(name, version, depth)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// GetServiceDependents
+//
+//	@BasePath		/api/v1/
+//	@Summary		List service dependents
+//	@Description	List the services whose current version depends on the service, transitively up to depth levels, i.e. what breaks if the service goes down
+//	@Tags			services
+//	@Accept			json
+//	@Param			name	path	string	true	"service name"
+//	@Param			depth	query	int		false	"levels of dependents to follow"	minimum(1)	maximum(32)	default(1)
+//	@Produce		application/json
+//	@Success		200	{object}	apiv1.DependencyGraph
+//	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/services/{name}/dependents [get]
+func (sc *ServiceController) GetServiceDependents(c *gin.Context) {
+	name := c.Param("name")
+	depth := c.Query("depth")
+	log.Info("received a request to list dependents of the service.", "name", name, "depth", depth)
+	response, err := sc.service.FetchDependents(name, depth)
 	if err != nil {
 		c.Error(err)
 		return
@@ -139,31 +447,40 @@ func GetServiceByName(c *gin.Context) {
 //	@Tags			services
 //	@Accept			json
 //	@Param			name	path	string	true	"service name"
-//	@Param			version	path	string	true	"version name"
+//	@Param			version	path	string	true	"semantic version, e.g. 1.4.0"
+//	@Param			as_of	query	string	false	"RFC3339 time to describe the version as it was at"	example(2026-01-31T12:00:00Z)
+//	@Param			If-None-Match	header	string	false	"ETag of the version as it was read, 304 is returned when it did not change since"
 //	@Produce		application/json
 //	@Success		200	{object}	model.Service
+//	@Success		304	"the version did not change since it was read"
+//	@Header			200,304	{string}	ETag	"revision of the version"
+//	@Header			200	{string}	Deprecation	"@<unix time> the version was deprecated at, when it is deprecated or retired"
+//	@Header			200	{string}	Sunset		"HTTP date the version is sunset at"
+//	@Header			200	{string}	Link		"successor service of the version, rel=successor-version"
 //	@Failure		400	{object}	generic.ErrorResponse
 //	@Failure		404	{object}	generic.ErrorResponse
 //	@Failure		500	{object}	generic.ErrorResponse
 //	@Router			/api/v1/services/{name}/{version} [get]
-func GetServiceNameAndVersion(c *gin.Context) {
+func (sc *ServiceController) GetServiceNameAndVersion(c *gin.Context) {
 	name := c.Param("name")
-	versionStr := c.Param("version")
-	version, err := util.StringToInt(versionStr)
-
+	version := c.Param("version")
+	log.Info("received a request to describe the service version.", "name", name, "version", version)
+	svc, err := sc.service.AsOf(c.Query("as_of"), name)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, generic.ErrorResponse{
-			Message: "version is not a int",
-			Error:   err.Error(),
-		})
+		c.Error(err)
 		return
 	}
-	log.Info("received a request to describe the service version.", "name", name, "version", version)
-	response, err := service.FetchByVersionAndName(name, version)
+	response, err := svc.FetchByVersionAndName(name, version)
 	if err != nil {
 		c.Error(err)
 		return
 	}
+	setDeprecationHeaders(c, response)
+	c.Header("ETag", etag(response.Revision))
+	if noneMatch(c.GetHeader("If-None-Match"), response.Revision) {
+		c.Status(http.StatusNotModified)
+		return
+	}
 	c.IndentedJSON(http.StatusOK, response)
 }
 
@@ -175,16 +492,17 @@ func GetServiceNameAndVersion(c *gin.Context) {
 //	@Tags			services
 //	@Accept			json
 //	@Param			name	path	string	true	"service name"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
 //	@Produce		application/json
 //	@Success		202	{object}	generic.Response
 //	@Failure		400	{object}	generic.ErrorResponse
 //	@Failure		404	{object}	generic.ErrorResponse
 //	@Failure		500	{object}	generic.ErrorResponse
 //	@Router			/api/v1/services/{name}/ [delete]
-func DeleteService(c *gin.Context) {
+func (sc *ServiceController) DeleteService(c *gin.Context) {
 	name := c.Param("name")
 	log.Info("received a request to delete the service.", "name", name)
-	err := service.Delete(name)
+	err := sc.as(c).Delete(name)
 	if err != nil {
 		c.Error(err)
 		return
@@ -192,17 +510,50 @@ func DeleteService(c *gin.Context) {
 	c.IndentedJSON(http.StatusAccepted, generic.Response{Message: fmt.Sprintf("service %s accepetd for deletion.", name)})
 }
 
-func GetAllServices(c *gin.Context) {
+// DeleteServiceVersion
+//
+//	@BasePath		/api/v1/
+//	@Summary		delete service version
+//	@Description	delete a single service version, the current version is only deleted with force and the service is then pointed at its highest remaining release
+//	@Tags			services
+//	@Accept			json
+//	@Param			name	path	string	true	"service name"
+//	@Param			version	path	string	true	"semantic version, e.g. 1.4.0"
+//	@Param			force	query	bool	false	"delete even if it is the current version"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
+//	@Produce		application/json
+//	@Success		202	{object}	generic.Response
+//	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		409	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/services/{name}/{version} [delete]
+func (sc *ServiceController) DeleteServiceVersion(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+	force := c.Query("force") == "true"
+	log.Info("received a request to delete the service version.", "name", name, "version", version, "force", force)
+	err := sc.as(c).DeleteVersion(name, version, force)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusAccepted, generic.Response{Message: fmt.Sprintf("service %s version %s accepted for deletion.", name, version)})
+}
+
+func (sc *ServiceController) GetAllServices(c *gin.Context) {
 	if c.Request.URL.RawQuery == "" {
-		getAllServices(c)
+		sc.getAllServices(c)
+	} else if c.Query("deleted") == "true" {
+		sc.getDeletedServices(c)
 	} else {
-		SearchAndSortServices(c)
+		sc.SearchAndSortServices(c)
 	}
 }
 
-func getAllServices(c *gin.Context) {
+func (sc *ServiceController) getAllServices(c *gin.Context) {
 	log.Info("received a request to get all services.")
-	res, err := service.FetchAll()
+	res, err := sc.service.FetchAll()
 	if err != nil {
 		c.Error(err)
 		return
@@ -210,6 +561,73 @@ func getAllServices(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, res)
 }
 
+func (sc *ServiceController) getDeletedServices(c *gin.Context) {
+	log.Info("received a request to get all deleted services.")
+	res, err := sc.service.FetchDeleted()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, res)
+}
+
+// RestoreService
+//
+//	@BasePath		/api/v1/
+//	@Summary		restore service
+//	@Description	undo the last deletion of the service, or of a single version when version is given
+//	@Tags			services
+//	@Accept			json
+//	@Param			name	path	string	true	"service name"
+//	@Param			version	query	string	false	"semantic version to restore, e.g. 1.4.0"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
+//	@Produce		application/json
+//	@Success		200	{object}	apiv1.Service{}
+//	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		409	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/services/{name}/restore [post]
+func (sc *ServiceController) RestoreService(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Query("version")
+	log.Info("received a request to restore the service.", "name", name, "version", version)
+	response, err := sc.as(c).Restore(name, version)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// GetFacets
+//
+//	@BasePath		/api/v1/
+//	@Summary		facet counts
+//	@Description	count the services matching the listing filters per label value, active state and owner, a service is counted once for every value one of its matching live versions carries
+//	@Tags			services
+//	@Produce		application/json
+//	@Param			query		query		string	false	"case-insensitive text to search for in the service name, description and tags"	example(payments)
+//	@Param			match		query		string	false	"find the query anywhere in a field or at its start, for tags at the start of any tag"	Enums(contains, prefix)	default(contains)
+//	@Param			fields		query		string	false	"comma separated fields to search, all of them by default"	example(name,tags)
+//	@Param			owner		query		string	false	"comma separated names of the teams owning the services"	example(payments-platform)
+//	@Param			labels		query		string	false	"kubernetes style label selector, the counted services have a version with matching labels"	example(tier=1,team in (payments,core))
+//	@Param			lifecycle	query		string	false	"comma separated lifecycle states, the counted services have a version in one of them"	example(production,deprecated)
+//	@Success		200			{object}	apiv1.Facets
+//	@Failure		400			{object}	generic.ErrorResponse
+//	@Failure		500			{object}	generic.ErrorResponse
+//	@Router			/api/v1/facets [get]
+func (sc *ServiceController) GetFacets(c *gin.Context) {
+	log.Info("received a request to count the facets of services with filters.")
+	search, _ := c.Get("search")
+	facets, err := sc.service.FetchFacets(search.(model.ServiceSearch))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, facets)
+}
+
 // SearchAndSortServices
 //
 //	@BasePath		/api/v1/
@@ -218,22 +636,48 @@ func getAllServices(c *gin.Context) {
 //	@Tags			services
 //	@Accept			json
 //	@Produce		application/json
-//	@Param			query		query		string	false	"query to filter, service name only"	example(Service1)
-//	@Param			dir			query		string	false	"direction of sorting"	Enums(desc, asc)
+//	@Param			query		query		string	false	"case-insensitive text to search for in the service name, description and tags"	example(payments)
+//	@Param			match		query		string	false	"find the query anywhere in a field or at its start, for tags at the start of any tag"	Enums(contains, prefix)	default(contains)
+//	@Param			fields		query		string	false	"comma separated fields to search, all of them by default"	example(name,tags)
+//	@Param			owner		query		string	false	"comma separated names of the teams owning the services"	example(payments-platform)
+//	@Param			labels		query		string	false	"kubernetes style label selector, the listed services have a version with matching labels"	example(tier=1,team in (payments,core))
+//	@Param			lifecycle	query		string	false	"comma separated lifecycle states, the listed services have a version in one of them"	example(production,deprecated)
+//	@Param			dir			query		string	false	"direction of the sort keys without a - or + prefix"	Enums(desc, asc)	default(desc)
 //	@Param			page		query		int		false	"page no"				minimum(1)	maximum(1000)
-//	@Param			sort		query		string	false	"sort by column name"	Enums(created_at, updated_at, version)
+//	@Param			sort		query		string	false	"comma separated sort keys out of name, created_at, updated_at, version, version_count, prefixed with - for descending or + for ascending"	default(created_at)	example(name,-updated_at)
 //	@Param			pagesize	query		int		false	"page size"				minimum(1)	maximum(10)
+//	@Param			cursor		query		string	false	"next or prev cursor of a previous page with the same sort, page is then ignored"
+//	@Param			deleted		query		bool	false	"list soft deleted service versions instead, other filters are ignored"
+//	@Param			as_of		query		string	false	"RFC3339 time to list the catalog as it was at, including services deleted since. The past catalog is sorted by name, ascending unless dir is desc, and can not be searched nor read by cursor"	example(2026-01-31T12:00:00Z)
 //	@Success		200			{object}	apiv1.ServicePagination
+//	@Failure		400			{object}	generic.ErrorResponse
 //	@Failure		500			{object}	generic.ErrorResponse
 //	@Router			/api/v1/services [get]
-func SearchAndSortServices(c *gin.Context) {
+func (sc *ServiceController) SearchAndSortServices(c *gin.Context) {
 	log.Info("received a request to get all services with filters.")
-	res, err := service.SearchAndSort(
-		c.GetString("query"),
-		c.GetString("sortBy"),
-		c.GetString("dir"),
-		c.GetInt("page"),
-		c.GetInt("pageSize"))
+	search, _ := c.Get("search")
+	sort, _ := c.Get("sort")
+	var res apiv1.ServicePagination
+	var err error
+	if asOf := c.Query("as_of"); asOf != "" {
+		// the past catalog is listed by name unless another sort is asked for, which is rejected.
+		if c.Query("sort") == "" {
+			sort = model.ServiceSort{{Key: model.SortName, Desc: c.Query("dir") == model.Desc}}
+		}
+		res, err = sc.service.SearchAndSortAsOf(asOf,
+			search.(model.ServiceSearch),
+			sort.(model.ServiceSort),
+			c.GetInt("page"),
+			c.GetInt("pageSize"),
+			c.GetString("cursor"))
+	} else {
+		res, err = sc.service.SearchAndSort(
+			search.(model.ServiceSearch),
+			sort.(model.ServiceSort),
+			c.GetInt("page"),
+			c.GetInt("pageSize"),
+			c.GetString("cursor"))
+	}
 	if err != nil {
 		c.Error(err)
 		return
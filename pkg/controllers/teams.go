@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/suyog1pathak/services/api/v1/generic"
+	"github.com/suyog1pathak/services/internal/service"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"github.com/suyog1pathak/services/pkg/model"
+	"github.com/suyog1pathak/services/pkg/util"
+	"net/http"
+)
+
+// TeamController serves the /api/v1/teams endpoints.
+type TeamController struct {
+	service *service.Service
+}
+
+func NewTeamController(service *service.Service) *TeamController {
+	return &TeamController{service: service}
+}
+
+// as returns the service acting for the caller of the request like ServiceController.as.
+func (tc *TeamController) as(c *gin.Context) *service.Service {
+	return tc.service.As(caller(c))
+}
+
+// CreateTeam
+//
+//	@BasePath		/api/v1/
+//	@Summary		create team
+//	@Description	create a team along with its owners
+//	@Tags			teams
+//	@Accept			json
+//	@Param			team	body	model.Team	true	"team, the name is 1 to 50 lowercase letters, digits or dashes"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
+//	@Produce		application/json
+//	@Success		201	{object}	model.Team
+//	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		409	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/teams [post]
+func (tc *TeamController) CreateTeam(c *gin.Context) {
+	reqBodyPtr, _ := c.Get("requestBody")
+	reqBody, _ := reqBodyPtr.(*model.Team)
+	log.Info("received a request to create a team.", "body", util.StructToJson(reqBody))
+	response, err := tc.as(c).CreateTeam(reqBody)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, response)
+}
+
+// GetTeams
+//
+//	@BasePath		/api/v1/
+//	@Summary		list teams
+//	@Description	list all teams with their owners
+//	@Tags			teams
+//	@Accept			json
+//	@Produce		application/json
+//	@Success		200	{object}	[]model.Team
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/teams [get]
+func (tc *TeamController) GetTeams(c *gin.Context) {
+	log.Info("received a request to get all teams.")
+	response, err := tc.service.FetchTeams()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// GetTeam
+//
+//	@BasePath		/api/v1/
+//	@Summary		get team
+//	@Description	get a team with its owners
+//	@Tags			teams
+//	@Accept			json
+//	@Param			team	path	string	true	"team name"
+//	@Produce		application/json
+//	@Success		200	{object}	model.Team
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/teams/{team} [get]
+func (tc *TeamController) GetTeam(c *gin.Context) {
+	name := c.Param("team")
+	log.Info("received a request to get the team.", "team", name)
+	response, err := tc.service.FetchTeam(name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// UpdateTeam
+//
+//	@BasePath		/api/v1/
+//	@Summary		update team
+//	@Description	update the description of a team, owners are added and removed with the owners endpoints
+//	@Tags			teams
+//	@Accept			json
+//	@Param			team	path	string		true	"team name"
+//	@Param			body	body	model.Team	true	"team, only the description is updated"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
+//	@Produce		application/json
+//	@Success		200	{object}	model.Team
+//	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/teams/{team} [patch]
+func (tc *TeamController) UpdateTeam(c *gin.Context) {
+	reqBodyPtr, _ := c.Get("requestBody")
+	reqBody, _ := reqBodyPtr.(*model.Team)
+	reqBody.Name = c.Param("team")
+	log.Info("received a request to update the team.", "body", util.StructToJson(reqBody))
+	response, err := tc.as(c).UpdateTeam(reqBody)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// DeleteTeam
+//
+//	@BasePath		/api/v1/
+//	@Summary		delete team
+//	@Description	delete a team and its owners, the team must not own any service
+//	@Tags			teams
+//	@Accept			json
+//	@Param			team	path	string	true	"team name"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
+//	@Produce		application/json
+//	@Success		202	{object}	generic.Response
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		409	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/teams/{team} [delete]
+func (tc *TeamController) DeleteTeam(c *gin.Context) {
+	name := c.Param("team")
+	log.Info("received a request to delete the team.", "team", name)
+	err := tc.as(c).DeleteTeam(name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusAccepted, generic.Response{Message: fmt.Sprintf("team %s accepted for deletion.", name)})
+}
+
+// AddOwner
+//
+//	@BasePath		/api/v1/
+//	@Summary		add owner
+//	@Description	add an owner to a team
+//	@Tags			teams
+//	@Accept			json
+//	@Param			team	path	string		true	"team name"
+//	@Param			owner	body	model.Owner	true	"owner"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
+//	@Produce		application/json
+//	@Success		201	{object}	model.Team
+//	@Failure		400	{object}	generic.ErrorResponse
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		409	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/teams/{team}/owners [post]
+func (tc *TeamController) AddOwner(c *gin.Context) {
+	name := c.Param("team")
+	reqBodyPtr, _ := c.Get("requestBody")
+	reqBody, _ := reqBodyPtr.(*model.Owner)
+	log.Info("received a request to add an owner to the team.", "team", name, "body", util.StructToJson(reqBody))
+	response, err := tc.as(c).AddOwner(name, reqBody)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, response)
+}
+
+// RemoveOwner
+//
+//	@BasePath		/api/v1/
+//	@Summary		remove owner
+//	@Description	remove an owner from a team
+//	@Tags			teams
+//	@Accept			json
+//	@Param			team	path	string	true	"team name"
+//	@Param			email	path	string	true	"owner email"
+//	@Param			X-User	header	string	false	"who makes the change, recorded in the audit log as sent, it is not authenticated"
+//	@Produce		application/json
+//	@Success		202	{object}	generic.Response
+//	@Failure		404	{object}	generic.ErrorResponse
+//	@Failure		500	{object}	generic.ErrorResponse
+//	@Router			/api/v1/teams/{team}/owners/{email} [delete]
+func (tc *TeamController) RemoveOwner(c *gin.Context) {
+	name := c.Param("team")
+	email := c.Param("email")
+	log.Info("received a request to remove an owner from the team.", "team", name, "email", email)
+	err := tc.as(c).RemoveOwner(name, email)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusAccepted, generic.Response{Message: fmt.Sprintf("owner %s removed from team %s.", email, name)})
+}
+
+// GetTeamServices
+//
+//	@BasePath		/api/v1/
+//	@Summary		list team services
+//	@Description	list the services owned by a team, with the search, sort and pagination of the service listing
+//	@Tags			teams
+//	@Accept			json
+//	@Produce		application/json
+//	@Param			team		path		string	true	"team name"
+//	@Param			query		query		string	false	"case-insensitive text to search for in the service name, description and tags"
+//	@Param			labels		query		string	false	"kubernetes style label selector, the listed services have a version with matching labels"
+//	@Param			lifecycle	query		string	false	"comma separated lifecycle states, the listed services have a version in one of them"	example(production,deprecated)
+//	@Param			sort		query		string	false	"comma separated sort keys out of name, created_at, updated_at, version, version_count, prefixed with - for descending or + for ascending"	default(created_at)
+//	@Param			dir			query		string	false	"direction of the sort keys without a - or + prefix"	Enums(desc, asc)	default(desc)
+//	@Param			page		query		int		false	"page no"	minimum(1)
+//	@Param			pagesize	query		int		false	"page size"	minimum(1)	maximum(100)
+//	@Param			cursor		query		string	false	"next or prev cursor of a previous page with the same sort, page is then ignored"
+//	@Success		200			{object}	ServicePagination
+//	@Failure		400			{object}	generic.ErrorResponse
+//	@Failure		404			{object}	generic.ErrorResponse
+//	@Failure		500			{object}	generic.ErrorResponse
+//	@Router			/api/v1/teams/{team}/services [get]
+func (tc *TeamController) GetTeamServices(c *gin.Context) {
+	name := c.Param("team")
+	log.Info("received a request to get the services of the team.", "team", name)
+	search, _ := c.Get("search")
+	sort, _ := c.Get("sort")
+	res, err := tc.service.FetchTeamServices(
+		name,
+		search.(model.ServiceSearch),
+		sort.(model.ServiceSort),
+		c.GetInt("page"),
+		c.GetInt("pageSize"),
+		c.GetString("cursor"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, res)
+}
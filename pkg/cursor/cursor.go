@@ -0,0 +1,65 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalid is returned for a token which is malformed or was not signed with the codec secret.
+var ErrInvalid = errors.New("invalid cursor")
+
+// Codec encodes values into opaque tokens signed with HMAC-SHA256, so clients can hand them back
+// but can not forge or alter them.
+type Codec struct {
+	secret []byte
+}
+
+// NewCodec returns a codec signing with the secret, a random secret is generated when it is empty
+// and the tokens are then only valid until the process restarts.
+func NewCodec(secret []byte) *Codec {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		_, _ = rand.Read(secret)
+	}
+	return &Codec{secret: secret}
+}
+
+// Encode returns the signed token of the value.
+func (c *Codec) Encode(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode verifies the token and decodes its value into v.
+func (c *Codec) Decode(token string, v interface{}) error {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return ErrInvalid
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return ErrInvalid
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalid
+	}
+	return nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,22 @@
+package cursor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDecodeShouldRejectForgedTokens(t *testing.T) {
+	type position struct{ Name string }
+	codec := NewCodec([]byte("secret"))
+	token, err := codec.Encode(position{Name: "payments"})
+	assert.NoError(t, err)
+
+	var decoded position
+	assert.NoError(t, codec.Decode(token, &decoded))
+	assert.Equal(t, "payments", decoded.Name)
+
+	forged, _ := NewCodec([]byte("other")).Encode(position{Name: "orders"})
+	for _, bad := range []string{"", "payments", token + "x", forged, "e30." + token[len(token)-43:]} {
+		assert.ErrorIs(t, codec.Decode(bad, &decoded), ErrInvalid, bad)
+	}
+}
@@ -6,5 +6,53 @@ const (
 	ErrServiceNotFound            = "service_not_found"
 	ErrServiceFoundWithSameName   = "service_found_with_the_same_name"
 	ErrServiceWithVersionNotFound = "service_with_provided_name_and_version_not_found"
+	ErrServiceVersionExists       = "service_with_provided_name_and_version_already_exists"
+	ErrServiceVersionConflict     = "service_version_allocation_conflict"
+	ErrInvalidVersion             = "invalid_semantic_version"
+	ErrInvalidVersionBump         = "invalid_version_bump"
+	ErrInvalidVersionConstraint   = "invalid_version_constraint"
+	ErrCurrentVersionNotFound     = "current_version_not_found"
+	ErrNoPreviousVersion          = "service_has_no_previous_version"
+	ErrCurrentVersionDelete       = "current_version_can_not_be_deleted"
+	ErrDeletedServiceNotFound     = "deleted_service_not_found"
+	ErrServiceRestoreConflict     = "live_service_found_with_the_same_name"
+	ErrInvalidSearch              = "invalid_search"
+	ErrInvalidSort                = "invalid_sort"
+	ErrInvalidCursor              = "invalid_cursor"
+	ErrInvalidLabels              = "invalid_labels"
+	ErrReadOnlyTags               = "read_only_tags"
+	ErrInvalidLifecycle           = "invalid_lifecycle"
+	ErrLifecycleTransition        = "illegal_lifecycle_transition"
+	ErrInvalidSunsetDate          = "invalid_sunset_date"
+	ErrInvalidSuccessor           = "invalid_successor"
+	ErrInvalidDays                = "invalid_days"
+	ErrInvalidLabelSelector       = "invalid_label_selector"
+	ErrTeamNotFound               = "team_not_found"
+	ErrTeamFoundWithSameName      = "team_found_with_the_same_name"
+	ErrTeamOwnsServices           = "team_owns_services"
+	ErrInvalidTeam                = "invalid_team"
+	ErrOwnerNotFound              = "owner_not_found"
+	ErrOwnerExists                = "owner_already_exists"
+	ErrUnknownOwner               = "service_owner_team_not_found"
+	ErrInvalidDependency          = "invalid_service_dependency"
+	ErrDependencyNotFound         = "service_dependency_not_found"
+	ErrDependencyCycle            = "service_dependency_cycle"
+	ErrInvalidDepth               = "invalid_depth"
+	ErrInvalidGraphDirection      = "invalid_graph_direction"
+	ErrInvalidGraphFormat         = "invalid_graph_format"
+	ErrInvalidDiffFormat          = "invalid_diff_format"
+	ErrInvalidChangelogFormat     = "invalid_changelog_format"
+	ErrInvalidAuditSearch         = "invalid_audit_search"
+	ErrInvalidAsOf                = "invalid_as_of"
+	ErrInvalidAsOfListing         = "invalid_as_of_listing"
+	ErrAsOfUnavailable            = "as_of_unavailable"
+	ErrRevisionMismatch           = "service_version_revision_mismatch"
+	ErrInvalidPatch               = "invalid_patch"
+	ErrPatchConflict              = "patch_conflict"
+	ErrUnsupportedMediaType       = "unsupported_media_type"
+	ErrInvalidIdempotencyKey      = "invalid_idempotency_key"
+	ErrIdempotencyKeyReused       = "idempotency_key_reused"
+	ErrIdempotencyKeyInProgress   = "idempotency_key_in_progress"
+	ErrPurgeDisabled              = "purge_is_disabled"
 	ErrHealthcheckDbFailed        = "healthcheck_failed_db_connection_issue"
 )
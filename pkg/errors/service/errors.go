@@ -33,6 +33,288 @@ func ServiceErrorHandler(errorString string) (apiv1generic.ErrorResponse, int) {
 			Error:   ErrServiceWithVersionNotFound,
 		}
 		return response, http.StatusNotFound
+	case ErrServiceVersionExists:
+		response := apiv1generic.ErrorResponse{
+			Message: "service with same name and version already exists.",
+			Error:   ErrServiceVersionExists,
+		}
+		return response, http.StatusConflict
+	case ErrServiceVersionConflict:
+		response := apiv1generic.ErrorResponse{
+			Message: "service version was published concurrently, please retry.",
+			Error:   ErrServiceVersionConflict,
+		}
+		return response, http.StatusConflict
+	case ErrInvalidVersion:
+		response := apiv1generic.ErrorResponse{
+			Message: "version is not a valid semantic version of up to 64 characters with parts up to 4294967295, e.g. 1.4.0 or 2.0.0-rc.1.",
+			Error:   ErrInvalidVersion,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidVersionBump:
+		response := apiv1generic.ErrorResponse{
+			Message: "bump must be one of major, minor or patch and can not be combined with an explicit version.",
+			Error:   ErrInvalidVersionBump,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidVersionConstraint:
+		response := apiv1generic.ErrorResponse{
+			Message: "version constraint is not valid, e.g. ^1.2, ~1.2.3 or >=1.0 <2.",
+			Error:   ErrInvalidVersionConstraint,
+		}
+		return response, http.StatusBadRequest
+	case ErrNoPreviousVersion:
+		response := apiv1generic.ErrorResponse{
+			Message: "service has no released version lower than the current version to roll back to.",
+			Error:   ErrNoPreviousVersion,
+		}
+		return response, http.StatusConflict
+	case ErrCurrentVersionDelete:
+		response := apiv1generic.ErrorResponse{
+			Message: "version is the current version of the service, promote another version first or delete with force=true.",
+			Error:   ErrCurrentVersionDelete,
+		}
+		return response, http.StatusConflict
+	case ErrDeletedServiceNotFound:
+		response := apiv1generic.ErrorResponse{
+			Message: "deleted service not found.",
+			Error:   ErrDeletedServiceNotFound,
+		}
+		return response, http.StatusNotFound
+	case ErrServiceRestoreConflict:
+		response := apiv1generic.ErrorResponse{
+			Message: "a live service with the same name or version was created since the deletion.",
+			Error:   ErrServiceRestoreConflict,
+		}
+		return response, http.StatusConflict
+	case ErrInvalidSearch:
+		response := apiv1generic.ErrorResponse{
+			Message: "invalid search, match must be contains or prefix and fields a comma separated list of name, description, tags.",
+			Error:   ErrInvalidSearch,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidSort:
+		response := apiv1generic.ErrorResponse{
+			Message: "invalid sort, sort must be a comma separated list of name, created_at, updated_at, version, version_count optionally prefixed with - or + and dir asc or desc.",
+			Error:   ErrInvalidSort,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidCursor:
+		response := apiv1generic.ErrorResponse{
+			Message: "invalid cursor, use the next or prev cursor of a page with the same sort.",
+			Error:   ErrInvalidCursor,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidLabels:
+		response := apiv1generic.ErrorResponse{
+			Message: "invalid labels, keys and values are up to 63 letters, digits, dashes, underscores or dots and keys may have a dns prefix.",
+			Error:   ErrInvalidLabels,
+		}
+		return response, http.StatusBadRequest
+	case ErrReadOnlyTags:
+		response := apiv1generic.ErrorResponse{
+			Message: "tags are derived from the labels and can not be written, write the labels instead.",
+			Error:   ErrReadOnlyTags,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidLabelSelector:
+		response := apiv1generic.ErrorResponse{
+			Message: "invalid label selector, e.g. tier=1,env!=dev,team in (payments,core),pci,!legacy.",
+			Error:   ErrInvalidLabelSelector,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidLifecycle:
+		response := apiv1generic.ErrorResponse{
+			Message: "the lifecycle is experimental, production, deprecated or retired.",
+			Error:   ErrInvalidLifecycle,
+		}
+		return response, http.StatusBadRequest
+	case ErrLifecycleTransition:
+		response := apiv1generic.ErrorResponse{
+			Message: "illegal lifecycle transition, versions move from experimental to production, deprecated or retired, from production to deprecated and from deprecated back to production or to retired.",
+			Error:   ErrLifecycleTransition,
+		}
+		return response, http.StatusConflict
+	case ErrInvalidSunsetDate:
+		response := apiv1generic.ErrorResponse{
+			Message: "only deprecated versions have a sunset date.",
+			Error:   ErrInvalidSunsetDate,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidSuccessor:
+		response := apiv1generic.ErrorResponse{
+			Message: "the successor of a deprecated version is another existing service.",
+			Error:   ErrInvalidSuccessor,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidDays:
+		response := apiv1generic.ErrorResponse{
+			Message: "days is a number of days between 0 and 3650.",
+			Error:   ErrInvalidDays,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidAuditSearch:
+		response := apiv1generic.ErrorResponse{
+			Message: "from and to are RFC3339 times with from before to, since_id is an event id and limit a number between 1 and 1000.",
+			Error:   ErrInvalidAuditSearch,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidAsOf:
+		response := apiv1generic.ErrorResponse{
+			Message: "as_of is an RFC3339 time, e.g. 2026-01-31T12:00:00Z.",
+			Error:   ErrInvalidAsOf,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidAsOfListing:
+		response := apiv1generic.ErrorResponse{
+			Message: "a listing as of a time is a page of services sorted by name, it can not be searched nor read by cursor.",
+			Error:   ErrInvalidAsOfListing,
+		}
+		return response, http.StatusBadRequest
+	case ErrAsOfUnavailable:
+		response := apiv1generic.ErrorResponse{
+			Message: "the catalog can not be rebuilt as of the time, versions live then have been purged since or a service deleted then has been restored.",
+			Error:   ErrAsOfUnavailable,
+		}
+		return response, http.StatusBadRequest
+	case ErrRevisionMismatch:
+		response := apiv1generic.ErrorResponse{
+			Message: "the version was changed since it was read, read it again and retry with its ETag in If-Match.",
+			Error:   ErrRevisionMismatch,
+		}
+		return response, http.StatusPreconditionFailed
+	case ErrInvalidPatch:
+		response := apiv1generic.ErrorResponse{
+			Message: "invalid patch, a merge patch is a JSON object and a JSON patch an array of operations, the patched version must be a valid version.",
+			Error:   ErrInvalidPatch,
+		}
+		return response, http.StatusBadRequest
+	case ErrPatchConflict:
+		response := apiv1generic.ErrorResponse{
+			Message: "the patch does not apply to the version, a location it refers to does not exist or a test failed.",
+			Error:   ErrPatchConflict,
+		}
+		return response, http.StatusConflict
+	case ErrUnsupportedMediaType:
+		response := apiv1generic.ErrorResponse{
+			Message: "unsupported media type, patches are only accepted by PATCH /api/v1/services/{name}/{version}.",
+			Error:   ErrUnsupportedMediaType,
+		}
+		return response, http.StatusUnsupportedMediaType
+	case ErrInvalidIdempotencyKey:
+		response := apiv1generic.ErrorResponse{
+			Message: "the Idempotency-Key header is at most 255 characters.",
+			Error:   ErrInvalidIdempotencyKey,
+		}
+		return response, http.StatusBadRequest
+	case ErrIdempotencyKeyReused:
+		response := apiv1generic.ErrorResponse{
+			Message: "the idempotency key was already used for another request, use a new key for every request.",
+			Error:   ErrIdempotencyKeyReused,
+		}
+		return response, http.StatusUnprocessableEntity
+	case ErrIdempotencyKeyInProgress:
+		response := apiv1generic.ErrorResponse{
+			Message: "a request with the same idempotency key is still in progress, retry later.",
+			Error:   ErrIdempotencyKeyInProgress,
+		}
+		return response, http.StatusConflict
+	case ErrTeamNotFound:
+		response := apiv1generic.ErrorResponse{
+			Message: "team not found.",
+			Error:   ErrTeamNotFound,
+		}
+		return response, http.StatusNotFound
+	case ErrTeamFoundWithSameName:
+		response := apiv1generic.ErrorResponse{
+			Message: "team with the same name already exists.",
+			Error:   ErrTeamFoundWithSameName,
+		}
+		return response, http.StatusConflict
+	case ErrTeamOwnsServices:
+		response := apiv1generic.ErrorResponse{
+			Message: "team still owns services, hand them over to another team first.",
+			Error:   ErrTeamOwnsServices,
+		}
+		return response, http.StatusConflict
+	case ErrInvalidTeam:
+		response := apiv1generic.ErrorResponse{
+			Message: "invalid team, the name must be 1 to 50 lowercase letters, digits or dashes and owners need distinct emails.",
+			Error:   ErrInvalidTeam,
+		}
+		return response, http.StatusBadRequest
+	case ErrOwnerNotFound:
+		response := apiv1generic.ErrorResponse{
+			Message: "owner not found.",
+			Error:   ErrOwnerNotFound,
+		}
+		return response, http.StatusNotFound
+	case ErrOwnerExists:
+		response := apiv1generic.ErrorResponse{
+			Message: "the team already has an owner with the same email.",
+			Error:   ErrOwnerExists,
+		}
+		return response, http.StatusConflict
+	case ErrUnknownOwner:
+		response := apiv1generic.ErrorResponse{
+			Message: "the owner of the service must be an existing team.",
+			Error:   ErrUnknownOwner,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidDependency:
+		response := apiv1generic.ErrorResponse{
+			Message: "dependencies must name distinct services other than the service itself, with valid semver constraints.",
+			Error:   ErrInvalidDependency,
+		}
+		return response, http.StatusBadRequest
+	case ErrDependencyNotFound:
+		response := apiv1generic.ErrorResponse{
+			Message: "a dependency of the service does not exist.",
+			Error:   ErrDependencyNotFound,
+		}
+		return response, http.StatusBadRequest
+	case ErrDependencyCycle:
+		response := apiv1generic.ErrorResponse{
+			Message: "the dependencies would create a cycle, one of them already depends on the service.",
+			Error:   ErrDependencyCycle,
+		}
+		return response, http.StatusConflict
+	case ErrInvalidDepth:
+		response := apiv1generic.ErrorResponse{
+			Message: "invalid depth, expected a number from 1 to 32.",
+			Error:   ErrInvalidDepth,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidGraphDirection:
+		response := apiv1generic.ErrorResponse{
+			Message: "invalid direction, expected dependencies or dependents.",
+			Error:   ErrInvalidGraphDirection,
+		}
+		return response, http.StatusBadRequest
+	case ErrInvalidGraphFormat:
+		response := apiv1generic.ErrorResponse{
+			Message: "the graph is rendered as dot (text/vnd.graphviz), mermaid (text/vnd.mermaid) or jgf (application/vnd.jgf+json, application/json).",
+			Error:   ErrInvalidGraphFormat,
+		}
+		return response, http.StatusNotAcceptable
+	case ErrInvalidDiffFormat:
+		response := apiv1generic.ErrorResponse{
+			Message: "the diff is rendered as json (application/json), patch (application/json-patch+json) or unified (text/x-diff, text/plain).",
+			Error:   ErrInvalidDiffFormat,
+		}
+		return response, http.StatusNotAcceptable
+	case ErrInvalidChangelogFormat:
+		response := apiv1generic.ErrorResponse{
+			Message: "the changelog is rendered as json (application/json) or markdown (text/markdown).",
+			Error:   ErrInvalidChangelogFormat,
+		}
+		return response, http.StatusNotAcceptable
+	case ErrPurgeDisabled:
+		response := apiv1generic.ErrorResponse{
+			Message: "purging is disabled, set app.purge_after to a retention window.",
+			Error:   ErrPurgeDisabled,
+		}
+		return response, http.StatusConflict
 	}
 
 	// default
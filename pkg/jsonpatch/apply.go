@@ -0,0 +1,274 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPatch is returned for a patch document that is not well formed.
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// ErrConflict is returned for a patch that cannot be applied to the document, when a location does not exist
+// or a test operation fails.
+var ErrConflict = errors.New("patch conflict")
+
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Op    string          `json:"op"`
+		Path  *string         `json:"path"`
+		From  *string         `json:"from"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Path == nil {
+		return fmt.Errorf("%w: %s operation without a path", ErrInvalidPatch, raw.Op)
+	}
+	*o = Operation{Op: raw.Op, Path: *raw.Path}
+	switch raw.Op {
+	case OpAdd, OpReplace, OpTest:
+		// a missing value is an error, a null one is a value.
+		if raw.Value == nil {
+			return fmt.Errorf("%w: %s operation without a value", ErrInvalidPatch, raw.Op)
+		}
+		return json.Unmarshal(raw.Value, &o.Value)
+	case OpMove, OpCopy:
+		if raw.From == nil {
+			return fmt.Errorf("%w: %s operation without a from", ErrInvalidPatch, raw.Op)
+		}
+		o.From = *raw.From
+	case OpRemove:
+	default:
+		return fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, raw.Op)
+	}
+	return nil
+}
+
+// Apply applies the operations to the JSON document in order and returns the patched document, nothing is
+// applied when an operation fails.
+func Apply(doc []byte, ops []Operation) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		var err error
+		if v, err = apply(v, op); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(v)
+}
+
+func apply(doc interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case OpAdd:
+		value, err := normalize(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpRemove:
+		doc, _, err := remove(doc, path)
+		return doc, err
+	case OpReplace:
+		value, err := normalize(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpMove:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Path != op.From && strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("%w: %s is moved into its own child %s", ErrInvalidPatch, op.From, op.Path)
+		}
+		doc, value, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpCopy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		// the copy must not share its maps and slices with the original.
+		if value, err = normalize(value); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpTest:
+		value, err := normalize(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		actual, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(actual, value) {
+			return nil, fmt.Errorf("%w: test of %s failed", ErrConflict, op.Path)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, op.Op)
+}
+
+// parsePointer returns the unescaped reference tokens of a JSON Pointer (RFC 6901).
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: %q is not a JSON pointer", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// index returns the array index a reference token points at, end allows "-" and the length of the array.
+func index(token string, array []interface{}, end bool) (int, error) {
+	if end && token == "-" {
+		return len(array), nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || (token != "0" && strings.HasPrefix(token, "0")) || token[0] == '+' {
+		return 0, fmt.Errorf("%w: %q is not an array index", ErrInvalidPatch, token)
+	}
+	if i < 0 || i > len(array) || (i == len(array) && !end) {
+		return 0, fmt.Errorf("%w: array index %d is out of bounds", ErrConflict, i)
+	}
+	return i, nil
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q does not exist", ErrConflict, token)
+			}
+			doc = value
+		case []interface{}:
+			i, err := index(token, node, false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("%w: %q does not exist", ErrConflict, token)
+		}
+	}
+	return doc, nil
+}
+
+// add returns the document with the value added at the path, arrays are replaced rather than changed in place.
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[token] = value
+		return doc, nil
+	case []interface{}:
+		i, err := index(token, node, true)
+		if err != nil {
+			return nil, err
+		}
+		array := append(append(append([]interface{}{}, node[:i]...), value), node[i:]...)
+		return add(doc, path[:len(path)-1], array)
+	}
+	return nil, fmt.Errorf("%w: %q does not exist", ErrConflict, token)
+}
+
+// remove returns the document without the value at the path, and the value.
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	token := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		value, ok := node[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: member %q does not exist", ErrConflict, token)
+		}
+		delete(node, token)
+		return doc, value, nil
+	case []interface{}:
+		i, err := index(token, node, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := node[i]
+		array := append(append([]interface{}{}, node[:i]...), node[i+1:]...)
+		doc, err = add(doc, path[:len(path)-1], array)
+		return doc, value, err
+	}
+	return nil, nil, fmt.Errorf("%w: %q does not exist", ErrConflict, token)
+}
+
+// MergePatch applies the JSON Merge Patch to the JSON document and returns the patched document. Members of
+// the patch set to null are removed, objects are merged member by member, any other value replaces the target.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var v, p interface{}
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return json.Marshal(merge(v, p))
+}
+
+func merge(target, patch interface{}) interface{} {
+	members, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	object, ok := target.(map[string]interface{})
+	if !ok {
+		object = map[string]interface{}{}
+	}
+	for key, value := range members {
+		if value == nil {
+			delete(object, key)
+		} else {
+			object[key] = merge(object[key], value)
+		}
+	}
+	return object
+}
@@ -0,0 +1,76 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplyShouldApplyTheOperationsInOrder(t *testing.T) {
+	doc := `{"describe": "payments", "isActive": true, "labels": {"tier": "1", "a/b": "x"}, "deps": ["ledger"]}`
+	var ops []Operation
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"op": "test", "path": "/isActive", "value": true},
+		{"op": "replace", "path": "/isActive", "value": false},
+		{"op": "replace", "path": "/describe", "value": ""},
+		{"op": "add", "path": "/deps/-", "value": "fraud"},
+		{"op": "add", "path": "/deps/0", "value": "auth"},
+		{"op": "remove", "path": "/labels/a~1b"},
+		{"op": "move", "from": "/labels/tier", "path": "/labels/level"},
+		{"op": "copy", "from": "/deps", "path": "/mirror"},
+		{"op": "remove", "path": "/mirror/1"},
+		{"op": "add", "path": "/owner", "value": null}
+	]`), &ops))
+	patched, err := Apply([]byte(doc), ops)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"describe": "",
+		"isActive": false,
+		"labels": {"level": "1"},
+		"deps": ["auth", "ledger", "fraud"],
+		"mirror": ["auth", "fraud"],
+		"owner": null
+	}`, string(patched))
+
+	for _, patch := range []string{
+		`[{"op": "test", "path": "/isActive", "value": false}]`,
+		`[{"op": "remove", "path": "/owner"}]`,
+		`[{"op": "replace", "path": "/deps/1", "value": "fraud"}]`,
+		`[{"op": "add", "path": "/labels/tier/x", "value": "1"}]`,
+	} {
+		assert.NoError(t, json.Unmarshal([]byte(patch), &ops))
+		_, err = Apply([]byte(doc), ops)
+		assert.ErrorIs(t, err, ErrConflict, patch)
+	}
+	for _, patch := range []string{
+		`[{"op": "add", "path": "/owner"}]`,
+		`[{"op": "replace", "value": 1}]`,
+		`[{"op": "move", "path": "/deps"}]`,
+		`[{"op": "merge", "path": "/deps"}]`,
+	} {
+		assert.ErrorIs(t, json.Unmarshal([]byte(patch), &ops), ErrInvalidPatch, patch)
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`[{"op": "move", "from": "/labels", "path": "/labels/tier"}]`), &ops))
+	_, err = Apply([]byte(doc), ops)
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+}
+
+func TestMergePatchShouldSetExactlyTheMembersSent(t *testing.T) {
+	doc := `{"describe": "payments", "isActive": true, "labels": {"tier": "1", "pci": "yes"}, "deps": ["ledger"]}`
+	patched, err := MergePatch([]byte(doc), []byte(`{
+		"describe": "",
+		"isActive": false,
+		"labels": {"pci": null, "team": "core"},
+		"deps": [],
+		"owner": null
+	}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"describe": "", "isActive": false, "labels": {"tier": "1", "team": "core"}, "deps": []}`, string(patched))
+
+	patched, err = MergePatch([]byte(doc), []byte(`{}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, doc, string(patched))
+
+	_, err = MergePatch([]byte(doc), []byte(`{"describe":`))
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+}
@@ -0,0 +1,128 @@
+// Package jsonpatch computes and applies JSON Patch documents (RFC 6902, https://www.rfc-editor.org/rfc/rfc6902)
+// and applies JSON Merge Patch documents (RFC 7396, https://www.rfc-editor.org/rfc/rfc7396).
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// media types of the patch documents.
+const (
+	MediaType           = "application/json-patch+json"
+	MergePatchMediaType = "application/merge-patch+json"
+)
+
+// operations of a patch, Diff only returns add, remove and replace operations.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// Operation is a single operation of a patch, Value is left out of remove operations.
+type Operation struct {
+	Op   string `json:"op" enums:"add,remove,replace,move,copy,test"`
+	Path string `json:"path" example:"/labels/tier"`
+	// From is the location a move or copy operation takes its value from.
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty" swaggertype:"object"`
+} //@name JSONPatchOperation
+
+func (o Operation) MarshalJSON() ([]byte, error) {
+	switch o.Op {
+	case OpRemove:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	case OpMove, OpCopy:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			From string `json:"from"`
+			Path string `json:"path"`
+		}{o.Op, o.From, o.Path})
+	}
+	// a null or falsy value is written out, it is what the member is set to.
+	return json.Marshal(struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// Diff returns the operations turning the JSON encoding of from into the JSON encoding of to. Objects are
+// compared member by member in the order of their keys, arrays and other values are replaced as a whole.
+func Diff(from, to interface{}) ([]Operation, error) {
+	a, err := normalize(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := normalize(to)
+	if err != nil {
+		return nil, err
+	}
+	return diff("", a, b, []Operation{}), nil
+}
+
+// normalize returns v as the maps, slices and scalars encoding/json decodes JSON into.
+func normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var n interface{}
+	err = json.Unmarshal(data, &n)
+	return n, err
+}
+
+func diff(path string, a, b interface{}, ops []Operation) []Operation {
+	objectA, okA := a.(map[string]interface{})
+	objectB, okB := b.(map[string]interface{})
+	if !okA || !okB {
+		if !reflect.DeepEqual(a, b) {
+			ops = append(ops, Operation{Op: OpReplace, Path: path, Value: b})
+		}
+		return ops
+	}
+	for _, key := range Keys(objectA, objectB) {
+		p := path + "/" + EscapeToken(key)
+		valueA, inA := objectA[key]
+		valueB, inB := objectB[key]
+		switch {
+		case !inB:
+			ops = append(ops, Operation{Op: OpRemove, Path: p})
+		case !inA:
+			ops = append(ops, Operation{Op: OpAdd, Path: p, Value: valueB})
+		default:
+			ops = diff(p, valueA, valueB, ops)
+		}
+	}
+	return ops
+}
+
+// Keys returns the keys of the objects, sorted.
+func Keys(objects ...map[string]interface{}) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, o := range objects {
+		for key := range o {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EscapeToken escapes a member name as a JSON Pointer (RFC 6901) reference token.
+func EscapeToken(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,46 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiffShouldCompareObjectsMemberByMember(t *testing.T) {
+	from := map[string]interface{}{
+		"describe": "payments",
+		"isActive": true,
+		"labels":   map[string]string{"tier": "1", "pci": "", "a/b": "x"},
+		"deps":     []string{"ledger"},
+	}
+	to := map[string]interface{}{
+		"describe": "payments api",
+		"isActive": false,
+		"labels":   map[string]string{"tier": "2", "team": "core"},
+		"deps":     []string{"ledger", "fraud"},
+		"owner":    nil,
+	}
+	ops, err := Diff(from, to)
+	assert.NoError(t, err)
+	patch, _ := json.Marshal(ops)
+	assert.JSONEq(t, `[
+		{"op": "replace", "path": "/deps", "value": ["ledger", "fraud"]},
+		{"op": "replace", "path": "/describe", "value": "payments api"},
+		{"op": "replace", "path": "/isActive", "value": false},
+		{"op": "remove", "path": "/labels/a~1b"},
+		{"op": "remove", "path": "/labels/pci"},
+		{"op": "add", "path": "/labels/team", "value": "core"},
+		{"op": "replace", "path": "/labels/tier", "value": "2"},
+		{"op": "add", "path": "/owner", "value": null}
+	]`, string(patch))
+
+	ops, err = Diff(from, from)
+	assert.NoError(t, err)
+	assert.Empty(t, ops)
+}
+
+func TestEscapeToken(t *testing.T) {
+	for s, want := range map[string]string{"tier": "tier", "a/b": "a~1b", "m~n": "m~0n", "~/": "~0~1"} {
+		assert.Equal(t, want, EscapeToken(s), s)
+	}
+}
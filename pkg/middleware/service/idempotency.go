@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"github.com/suyog1pathak/services/pkg/model"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	headerIdempotencyKey = "Idempotency-Key"
+	// headerReplayed marks a response replayed from an earlier request with the same key.
+	headerReplayed = "Idempotent-Replayed"
+	// headerUser names the caller a key belongs to.
+	headerUser = "X-User"
+	// maxIdempotencyKeyLength is the longest key accepted.
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency makes requests with an Idempotency-Key header safe to retry. A key belongs to the method, path and
+// X-User header of the request it is sent with, the same key sent to another route or by another caller is another
+// key. The response of the first request with a key is stored for the ttl and replayed to the requests retrying it,
+// a request reusing the key with another body is rejected with 422 and one made while the first is still in
+// progress with 409. A request in progress holds its key for the lease, the ttl when it is zero, a retry made after
+// it takes the key over as the first request was likely lost along with the server processing it. Only final
+// outcomes are stored: server errors, conflicts and the other responses inviting a retry are not, so the request
+// can be retried with the key. Requests without the header and a zero ttl are passed through.
+//
+// It goes before ServiceErrorHandler so the error responses it writes are stored as well.
+func Idempotency(repo model.IdempotencyRepository, ttl, lease time.Duration) gin.HandlerFunc {
+	if lease <= 0 {
+		lease = ttl
+	}
+	return func(c *gin.Context) {
+		key := c.GetHeader(headerIdempotencyKey)
+		if key == "" || ttl <= 0 {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			abortWithError(c, customerrors.ErrInvalidIdempotencyKey)
+			return
+		}
+		body, err := c.GetRawData()
+		if err != nil {
+			log.Warn("unable to read the request body", "error", err.Error())
+			abortWithError(c, customerrors.ErrInternalServer)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &model.IdempotencyKey{Key: scope(c.Request, key), Fingerprint: fingerprint(body), ExpiresAt: time.Now().Add(lease)}
+		stored, reserved, err := repo.Reserve(record)
+		if err != nil {
+			abortWithError(c, customerrors.ErrInternalServer)
+			return
+		}
+		if !reserved {
+			switch {
+			case stored.Fingerprint != record.Fingerprint:
+				log.Warn("idempotency key reused for another request", "key", key)
+				abortWithError(c, customerrors.ErrIdempotencyKeyReused)
+			case stored.StatusCode == 0:
+				abortWithError(c, customerrors.ErrIdempotencyKeyInProgress)
+			default:
+				log.Info("replaying the response of an idempotent request", "key", key, "status", stored.StatusCode)
+				replay(c, stored)
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		defer func() {
+			// a panic or a response inviting a retry leaves nothing to replay, the request can be retried.
+			if r := recover(); r != nil {
+				_ = repo.Release(record)
+				panic(r)
+			}
+			if retryable(recorder.Status()) {
+				_ = repo.Release(record)
+				return
+			}
+			header := http.Header{}
+			for name, values := range recorder.Header() {
+				if name != "X-Request-Id" {
+					header[name] = values
+				}
+			}
+			record.ExpiresAt = time.Now().Add(ttl)
+			record.StatusCode = recorder.Status()
+			record.Header, _ = json.Marshal(header)
+			record.Body = recorder.body.Bytes()
+			if err := repo.Complete(record); err != nil {
+				_ = repo.Release(record)
+			}
+		}()
+		c.Next()
+	}
+}
+
+// retryable reports whether a response invites the request to be retried rather than being its final outcome.
+func retryable(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	}
+	return status >= http.StatusInternalServerError
+}
+
+// scope returns the stored key of an Idempotency-Key, a hash of the key along with the method, path and caller
+// of the request.
+func scope(r *http.Request, key string) string {
+	h := sha256.Sum256([]byte(r.Method + "\n" + r.URL.Path + "\n" + r.Header.Get(headerUser) + "\n" + key))
+	return hex.EncodeToString(h[:])
+}
+
+// fingerprint identifies a request by its body, the method and path are part of the scope of its key.
+func fingerprint(body []byte) string {
+	h := sha256.Sum256(body)
+	return hex.EncodeToString(h[:])
+}
+
+// replay writes the stored response of an earlier request.
+func replay(c *gin.Context, stored model.IdempotencyKey) {
+	var header http.Header
+	_ = json.Unmarshal(stored.Header, &header)
+	for name, values := range header {
+		c.Writer.Header()[name] = values
+	}
+	c.Header(headerReplayed, "true")
+	c.Status(stored.StatusCode)
+	_, _ = c.Writer.Write(stored.Body)
+	c.Abort()
+}
+
+func abortWithError(c *gin.Context, code string) {
+	response, status := customerrors.ServiceErrorHandler(code)
+	c.IndentedJSON(status, response)
+	c.Abort()
+}
+
+// responseRecorder keeps a copy of the response body written through it.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/suyog1pathak/services/pkg/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyShouldReplayTheStoredResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	router := gin.New()
+	router.POST("/services", Idempotency(model.NewMemoryIdempotencyRepository(), time.Hour, time.Minute), func(c *gin.Context) {
+		calls++
+		body, _ := c.GetRawData()
+		c.Header("ETag", `"1"`)
+		c.String(http.StatusCreated, "created %s #%d", body, calls)
+	})
+	send := func(key, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/services", strings.NewReader(body))
+		if key != "" {
+			r.Header.Set(headerIdempotencyKey, key)
+		}
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	first := send("deploy-1", "payments")
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, "created payments #1", first.Body.String())
+	retry := send("deploy-1", "payments")
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "created payments #1", retry.Body.String())
+	assert.Equal(t, `"1"`, retry.Header().Get("ETag"))
+	assert.Equal(t, "true", retry.Header().Get(headerReplayed))
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, send("deploy-1", "ledger").Code)
+	assert.Equal(t, http.StatusBadRequest, send(strings.Repeat("k", 256), "payments").Code)
+	assert.Equal(t, "created payments #2", send("", "payments").Body.String())
+	assert.Equal(t, "created payments #3", send("deploy-2", "payments").Body.String())
+}
+
+func TestIdempotencyShouldScopeKeysToTheRouteAndCaller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	router := gin.New()
+	router.PATCH("/services/:name", Idempotency(model.NewMemoryIdempotencyRepository(), time.Hour, time.Minute), func(c *gin.Context) {
+		calls++
+		c.String(http.StatusCreated, "%s #%d", c.Param("name"), calls)
+	})
+	send := func(user, path string) string {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPatch, path, strings.NewReader(`{"version":"2.0.0"}`))
+		r.Header.Set(headerIdempotencyKey, "release-2")
+		r.Header.Set(headerUser, user)
+		router.ServeHTTP(w, r)
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "payments #1", send("jane", "/services/payments"))
+	assert.Equal(t, "payments #1", send("jane", "/services/payments"))
+	// the same key and body for another service or from another caller is another request.
+	assert.Equal(t, "ledger #2", send("jane", "/services/ledger"))
+	assert.Equal(t, "payments #3", send("john", "/services/payments"))
+	assert.Equal(t, "payments #3", send("john", "/services/payments"))
+}
+
+func TestIdempotencyShouldNotStoreResponsesInvitingARetry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := model.NewMemoryIdempotencyRepository()
+	status := http.StatusServiceUnavailable
+	router := gin.New()
+	router.POST("/services", Idempotency(repo, time.Hour, time.Minute), func(c *gin.Context) {
+		c.Status(status)
+	})
+	send := func() int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/services", nil)
+		r.Header.Set(headerIdempotencyKey, "deploy-1")
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, send())
+	status = http.StatusConflict
+	assert.Equal(t, http.StatusConflict, send())
+	status = http.StatusTooManyRequests
+	assert.Equal(t, http.StatusTooManyRequests, send())
+	status = http.StatusCreated
+	assert.Equal(t, http.StatusCreated, send())
+	status = http.StatusServiceUnavailable
+	assert.Equal(t, http.StatusCreated, send())
+
+	// a request still in progress is not repeated.
+	_, reserved, _ := repo.Reserve(&model.IdempotencyKey{Key: "deploy-2", ExpiresAt: time.Now().Add(time.Hour)})
+	assert.True(t, reserved)
+	_, reserved, _ = repo.Reserve(&model.IdempotencyKey{Key: "deploy-2", ExpiresAt: time.Now().Add(time.Hour)})
+	assert.False(t, reserved)
+	// an expired key is reserved again.
+	_, reserved, _ = repo.Reserve(&model.IdempotencyKey{Key: "deploy-3", ExpiresAt: time.Now()})
+	assert.True(t, reserved)
+	_, reserved, _ = repo.Reserve(&model.IdempotencyKey{Key: "deploy-3", ExpiresAt: time.Now().Add(time.Hour)})
+	assert.True(t, reserved)
+}
+
+func TestIdempotencyShouldTakeOverAReservationPastItsLease(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := model.NewMemoryIdempotencyRepository()
+	router := gin.New()
+	router.POST("/services", Idempotency(repo, time.Hour, time.Minute), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	send := func() int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/services", nil)
+		r.Header.Set(headerIdempotencyKey, "deploy-1")
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+	r := httptest.NewRequest(http.MethodPost, "/services", nil)
+	lost := &model.IdempotencyKey{Key: scope(r, "deploy-1"), Fingerprint: fingerprint(nil), ExpiresAt: time.Now().Add(time.Minute)}
+	_, reserved, _ := repo.Reserve(lost)
+	assert.True(t, reserved)
+	assert.Equal(t, http.StatusConflict, send())
+
+	// the request holding the key was lost, a retry past its lease takes the key over.
+	_ = repo.Release(lost)
+	lost.ID, lost.ExpiresAt = 0, time.Now()
+	_, reserved, _ = repo.Reserve(lost)
+	assert.True(t, reserved)
+	assert.Equal(t, http.StatusCreated, send())
+	// the response outlives the lease, and the lost request completing late does not overwrite it.
+	lost.StatusCode = http.StatusAccepted
+	_ = repo.Complete(lost)
+	_ = repo.Release(lost)
+	assert.Equal(t, http.StatusCreated, send())
+	stored, reserved, _ := repo.Reserve(&model.IdempotencyKey{Key: lost.Key, ExpiresAt: time.Now().Add(time.Minute)})
+	assert.False(t, reserved)
+	assert.True(t, stored.ExpiresAt.After(time.Now().Add(30*time.Minute)))
+}
@@ -1,15 +1,47 @@
 package middleware
 
 import (
+	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/suyog1pathak/services/api/v1/generic"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	"github.com/suyog1pathak/services/pkg/jsonpatch"
+	log "github.com/suyog1pathak/services/pkg/logger"
 	"github.com/suyog1pathak/services/pkg/model"
 	"github.com/suyog1pathak/services/pkg/util"
 	"net/http"
+	"slices"
+	"strings"
 )
 
-func ServiceBodyValidation() gin.HandlerFunc {
+// ServiceBodyValidation binds the request body to a model.Service. A body of one of the given patch media types,
+// a JSON Merge Patch or a JSON Patch, is checked and set as the patch along with its media type instead, the other
+// patch media types are unsupported.
+func ServiceBodyValidation(patchTypes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if contentType := c.ContentType(); contentType == jsonpatch.MergePatchMediaType || contentType == jsonpatch.MediaType {
+			if !slices.Contains(patchTypes, contentType) {
+				log.Warn("unsupported patch media type", "contentType", contentType)
+				c.Error(errors.New(customerrors.ErrUnsupportedMediaType))
+				c.Abort()
+				return
+			}
+			patch, err := c.GetRawData()
+			if err == nil {
+				err = validatePatch(contentType, patch)
+			}
+			if err != nil {
+				log.Warn("invalid patch", "error", err.Error())
+				c.Error(errors.New(customerrors.ErrInvalidPatch))
+				c.Abort()
+				return
+			}
+			c.Set("patch", patch)
+			c.Set("patchType", contentType)
+			c.Next()
+			return
+		}
 		var requestBody model.Service
 		// schema validation
 		if err := c.BindJSON(&requestBody); err != nil {
@@ -27,34 +59,112 @@ func ServiceBodyValidation() gin.HandlerFunc {
 	}
 }
 
+// validatePatch checks a merge patch is a JSON object and a JSON patch an array of well formed operations.
+func validatePatch(contentType string, patch []byte) error {
+	if contentType == jsonpatch.MediaType {
+		var ops []jsonpatch.Operation
+		return json.Unmarshal(patch, &ops)
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil {
+		return err
+	}
+	if members == nil {
+		return errors.New("the merge patch is not an object")
+	}
+	return nil
+}
+
+// TeamBodyValidation binds the request body to a model.Team.
+func TeamBodyValidation() gin.HandlerFunc {
+	return bodyValidation(func() interface{} { return &model.Team{} })
+}
+
+// OwnerBodyValidation binds the request body to a model.Owner.
+func OwnerBodyValidation() gin.HandlerFunc {
+	return bodyValidation(func() interface{} { return &model.Owner{} })
+}
+
+func bodyValidation(newBody func() interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestBody := newBody()
+		// schema validation
+		if err := c.BindJSON(requestBody); err != nil {
+			res := generic.ErrorResponse{
+				Message: "request body validation failed",
+				Error:   err.Error(),
+			}
+			c.IndentedJSON(http.StatusBadRequest, res)
+			c.Abort()
+			return
+		}
+
+		c.Set("requestBody", requestBody)
+		c.Next()
+	}
+}
+
+// ServiceQueryParams validates the search, sort and pagination query params of the service listing.
 func ServiceQueryParams() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if c.Request.URL.RawQuery != "" {
-			// :todo validations
-			query := c.DefaultQuery("query", "%")
-			sort := c.DefaultQuery("sort", "created_at")
-			dir := c.DefaultQuery("dir", "desc")
-			page, _ := util.StringToInt(c.DefaultQuery("page", "1"))
-			pageSize, _ := util.StringToInt(c.DefaultQuery("pagesize", "10"))
-
-			if page <= 0 {
-				page = 1
-			}
-
-			switch {
-			// max page size
-			case pageSize > 100:
-				pageSize = 100
-			// min page size
-			case pageSize <= 0:
-				pageSize = 1
-			}
-			c.Set("query", query)
-			c.Set("sortBy", sort)
-			c.Set("dir", dir)
-			c.Set("page", page)
-			c.Set("pageSize", pageSize)
+		search, err := model.NewServiceSearch(c.Query("query"), c.Query("match"), c.Query("fields"))
+		if err != nil {
+			log.Warn("invalid search", "error", err.Error())
+			c.Error(errors.New(customerrors.ErrInvalidSearch))
+			c.Abort()
+			return
+		}
+		for _, owner := range strings.Split(c.Query("owner"), ",") {
+			if owner = strings.TrimSpace(owner); owner != "" {
+				search.Owners = append(search.Owners, owner)
+			}
+		}
+		for _, lifecycle := range strings.Split(c.Query("lifecycle"), ",") {
+			if lifecycle = strings.TrimSpace(lifecycle); lifecycle == "" {
+				continue
+			}
+			if !model.IsLifecycle(lifecycle) {
+				log.Warn("invalid lifecycle", "lifecycle", lifecycle)
+				c.Error(errors.New(customerrors.ErrInvalidLifecycle))
+				c.Abort()
+				return
+			}
+			search.Lifecycles = append(search.Lifecycles, lifecycle)
+		}
+		search.Labels, err = model.ParseLabelSelector(c.Query("labels"))
+		if err != nil {
+			log.Warn("invalid label selector", "error", err.Error())
+			c.Error(errors.New(customerrors.ErrInvalidLabelSelector))
+			c.Abort()
+			return
+		}
+		sort, err := model.ParseServiceSort(c.Query("sort"), c.Query("dir"))
+		if err != nil {
+			log.Warn("invalid sort", "error", err.Error())
+			c.Error(errors.New(customerrors.ErrInvalidSort))
+			c.Abort()
+			return
+		}
+		page, _ := util.StringToInt(c.DefaultQuery("page", "1"))
+		pageSize, _ := util.StringToInt(c.DefaultQuery("pagesize", "10"))
+
+		if page <= 0 {
+			page = 1
+		}
+
+		switch {
+		// max page size
+		case pageSize > 100:
+			pageSize = 100
+		// min page size
+		case pageSize <= 0:
+			pageSize = 1
 		}
+		c.Set("search", search)
+		c.Set("sort", sort)
+		c.Set("cursor", c.Query("cursor"))
+		c.Set("page", page)
+		c.Set("pageSize", pageSize)
 		c.Next()
 	}
 }
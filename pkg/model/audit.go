@@ -0,0 +1,76 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// actions recorded in the audit log.
+const (
+	AuditCreate        = "create"
+	AuditCreateVersion = "create_version"
+	AuditUpdateVersion = "update_version"
+	AuditPromote       = "promote"
+	AuditRollback      = "rollback"
+	AuditDelete        = "delete"
+	AuditDeleteVersion = "delete_version"
+	AuditRestore       = "restore"
+	AuditPurge         = "purge"
+	AuditCreateTeam    = "create_team"
+	AuditUpdateTeam    = "update_team"
+	AuditDeleteTeam    = "delete_team"
+	AuditAddOwner      = "add_owner"
+	AuditRemoveOwner   = "remove_owner"
+)
+
+// AnonymousActor is recorded for changes made without an X-User header.
+const AnonymousActor = "anonymous"
+
+// AuditEvent is a change made to a service or to a team. Before and After are the JSON of what the change touched,
+// null when it did not exist before or does not anymore. Events are only ever appended, they are kept
+// when the service is purged.
+type AuditEvent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Actor is who made the change, the X-User header of the request.
+	Actor  string `json:"actor" example:"jane.doe"`
+	Action string `json:"action" enums:"create,create_version,update_version,promote,rollback,delete,delete_version,restore,purge,create_team,update_team,delete_team,add_owner,remove_owner"`
+	// Service is the name of the changed service, empty for changes to teams.
+	Service string `json:"serviceName,omitempty" example:"payments"`
+	// Team is the name of the changed team, empty for changes to services.
+	Team string `json:"teamName,omitempty" example:"payments-platform"`
+	// Version is the changed version, empty for changes to the service as a whole.
+	Version   string          `json:"version,omitempty" example:"1.4.0"`
+	Before    json.RawMessage `json:"before" swaggertype:"object"`
+	After     json.RawMessage `json:"after" swaggertype:"object"`
+	RequestID string          `json:"requestId,omitempty" example:"4b1c8e5e-0c6c-4f43-9d1b-1f0f6a8e2f55"`
+} //@name AuditEvent
+
+// AuditSearch selects the events returned by GetAuditEvents, zero fields match every event.
+type AuditSearch struct {
+	Service string
+	Team    string
+	// Services limits the search to the events of the given services.
+	Services []string
+	Actor    string
+	// Actions limits the search to the events of the given actions.
+	Actions []string
+	// From and To limit the search to the events created in [From, To).
+	From time.Time
+	To   time.Time
+	// SinceID limits the search to the events appended after the event with the id.
+	SinceID uint
+	Limit   int
+}
+
+// Matches reports whether the event is selected by the search, the limit aside.
+func (s AuditSearch) Matches(e AuditEvent) bool {
+	return e.ID > s.SinceID &&
+		(s.Service == "" || e.Service == s.Service) &&
+		(s.Team == "" || e.Team == s.Team) &&
+		(len(s.Services) == 0 || contains(s.Services, e.Service)) &&
+		(s.Actor == "" || e.Actor == s.Actor) &&
+		(len(s.Actions) == 0 || contains(s.Actions, e.Action)) &&
+		(s.From.IsZero() || !e.CreatedAt.Before(s.From)) &&
+		(s.To.IsZero() || e.CreatedAt.Before(s.To))
+}
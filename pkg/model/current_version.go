@@ -0,0 +1,24 @@
+package model
+
+import (
+	"github.com/suyog1pathak/services/pkg/semver"
+	"time"
+)
+
+// CurrentVersion points at the version of a service served as current.
+// It is stored apart from the version rows so a service can be promoted and rolled back.
+type CurrentVersion struct {
+	Name       string `gorm:"primaryKey"`
+	Version    string
+	VersionKey string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func NewCurrentVersion(name string, v semver.Version) *CurrentVersion {
+	return &CurrentVersion{
+		Name:       name,
+		Version:    v.String(),
+		VersionKey: v.Key(),
+	}
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Dependency is a service a service version depends on, optionally limited to the versions matching a semver constraint.
+type Dependency struct {
+	ID        uint   `json:"-" gorm:"primaryKey"`
+	ServiceID uint   `json:"-"`
+	Name      string `json:"serviceName" example:"ledger"`
+	// Constraint is a semver constraint on the versions of the dependency, any version when it is empty.
+	Constraint string    `json:"constraint,omitempty" gorm:"column:version_constraint" example:"^1.2"`
+	CreatedAt  time.Time `json:"-"`
+} //@name Dependency
+
+func (Dependency) TableName() string {
+	return "service_dependencies"
+}
+
+// DependencyEdge is a dependency declared by a live service version.
+type DependencyEdge struct {
+	Service    string `json:"serviceName"`
+	Version    string `json:"version" example:"1.4.0"`
+	Dependency string `json:"dependency"`
+	Constraint string `json:"constraint,omitempty"`
+	// Current is set on the edges of the version served as current.
+	Current bool `json:"-"`
+}
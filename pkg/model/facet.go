@@ -0,0 +1,19 @@
+package model
+
+// facets of the services counted by GetFacetCounts.
+const (
+	FacetLabel  = "label"
+	FacetActive = "active"
+	FacetOwner  = "owner"
+	// FacetTotal is the number of services matching the search.
+	FacetTotal = "total"
+)
+
+// FacetCount is the number of services with a live version matching a search which has the value of a facet,
+// Key is the key of a label facet. The active facet has the values "true" and "false".
+type FacetCount struct {
+	Facet string
+	Key   string
+	Value string
+	Count int64
+}
@@ -0,0 +1,713 @@
+package model
+
+import (
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"slices"
+	"time"
+)
+
+const (
+	mysqlErrDuplicateEntry  = 1062
+	mysqlErrLockDeadlock    = 1213
+	mysqlErrNoReferencedRow = 1452
+
+	// maxVersionAttempts is how many times AddNextVersion allocates a version before giving up.
+	maxVersionAttempts = 5
+	// maxTransactionAttempts is how many times Transaction runs a transaction rolled back by a deadlock.
+	maxTransactionAttempts = 5
+)
+
+// GormServiceRepository is the mysql implementation of ServiceRepository.
+type GormServiceRepository struct {
+	db *gorm.DB
+	// inTransaction is set on the repository Transaction hands out.
+	inTransaction bool
+}
+
+func NewGormServiceRepository(db *gorm.DB) *GormServiceRepository {
+	return &GormServiceRepository{db: db}
+}
+
+func (r *GormServiceRepository) Add(s *Service) error {
+	log.Debug("adding service", "service", s.Name)
+	s.Revision = 1
+	result := r.db.Create(s)
+	if result.Error != nil {
+		log.Error("error in adding services", "service", s.Name, "error", result.Error.Error())
+		if isMysqlError(result.Error, mysqlErrDuplicateEntry) {
+			return errors.New(customerrors.ErrServiceVersionExists)
+		}
+		if isMysqlError(result.Error, mysqlErrNoReferencedRow) {
+			return errors.New(customerrors.ErrUnknownOwner)
+		}
+		return result.Error
+	}
+	return nil
+}
+
+func (r *GormServiceRepository) AddNextVersion(s *Service, bump string) error {
+	for attempt := 1; ; attempt++ {
+		log.Debug("adding next service version", "service", s.Name, "attempt", attempt)
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			var live int64
+			if err := tx.Model(&Service{}).Where("name = ?", s.Name).Count(&live).Error; err != nil {
+				return err
+			}
+			if live == 0 {
+				return errors.New(customerrors.ErrServiceNotFound)
+			}
+			// the locking read serializes concurrent publishers on the latest version row,
+			// the unique index on (name, version) catches whatever slips through. soft deleted versions
+			// count, their numbers are not handed out again so they can still be restored.
+			var latest Service
+			result := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("name = ?", s.Name).Order("version_key desc").Limit(1).Find(&latest)
+			if result.Error != nil {
+				return result.Error
+			}
+			next, err := latest.SemVer().Bump(bump)
+			if err != nil {
+				log.Warn("unable to bump the latest version", "service", s.Name, "error", err.Error())
+				return errors.New(customerrors.ErrInvalidVersion)
+			}
+			s.ID = 0
+			for i := range s.Labels {
+				s.Labels[i].ID = 0
+			}
+			for i := range s.Dependencies {
+				s.Dependencies[i].ID = 0
+			}
+			s.SetVersion(next)
+			s.Revision = 1
+			return tx.Create(s).Error
+		})
+		if err == nil {
+			return nil
+		}
+		// a deadlock rolls back the enclosing transaction as a whole, Transaction runs it again.
+		if !isMysqlError(err, mysqlErrDuplicateEntry, mysqlErrLockDeadlock) || r.inTransaction && isMysqlError(err, mysqlErrLockDeadlock) {
+			log.Error("error in adding next service version", "service", s.Name, "error", err.Error())
+			return err
+		}
+		if attempt == maxVersionAttempts {
+			log.Error("giving up on adding next service version", "service", s.Name, "attempts", attempt, "error", err.Error())
+			return errors.New(customerrors.ErrServiceVersionConflict)
+		}
+		log.Warn("conflict while adding next service version, retrying", "service", s.Name, "attempt", attempt, "error", err.Error())
+		time.Sleep(time.Duration(attempt) * 10 * time.Millisecond)
+	}
+}
+
+func (r *GormServiceRepository) List() ([]Service, error) {
+	log.Debug("fetching all services")
+	var output []Service
+	result := r.db.Where("is_active = ?", true).Find(&output)
+	if result.Error != nil {
+		log.Error("error in listing services", "error", result.Error.Error())
+		return output, result.Error
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) GetByName(name string) ([]Service, error) {
+	log.Debug("fetching service by name", "service", name)
+	var output []Service
+	result := r.db.Preload("Labels").Preload("Dependencies").Where("name = ?", name).Order("version_key").Find(&output)
+	if result.Error != nil {
+		log.Error("error in getting service by name", "name", name, "error", result.Error.Error())
+		return output, result.Error
+	}
+	if result.RowsAffected == 0 {
+		log.Warn("service not found", "name", name)
+		return output, errors.New(customerrors.ErrServiceNotFound)
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) GetByNameCount(name string) (int64, error) {
+	log.Debug("fetching count service by name", "service", name)
+	var output int64
+	result := r.db.Model(&Service{}).Debug().Where("name = ?", name).Count(&output)
+	if result.Error != nil {
+		log.Error("error in fetching count service by name", "name", name, "error", result.Error.Error())
+		return output, result.Error
+	}
+	if result.RowsAffected == 0 {
+		log.Warn("service not found", "name", name)
+		return output, errors.New(customerrors.ErrServiceNotFound)
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) GetServiceAndVersionCounts(search ServiceSearch, sort ServiceSort, page ServicePage) ([]ServiceCount, int64, error) {
+	log.Debug("fetching service and version counts", "query", search.Query, "match", search.Match, "fields", search.Fields,
+		"sort", sort.String(), "limit", page.Limit, "offset", page.Offset, "after", page.After != nil, "before", page.Before != nil)
+	/*
+		SELECT * FROM (
+			SELECT name, COUNT(*) as count, COUNT(*) OVER () as total,
+				MAX(created_at) as created_at, MAX(updated_at) as updated_at, MAX(version_key) as version_key
+			FROM `services` WHERE (LOWER(name) LIKE '%pay%' OR ...) AND `services`.`deleted_at` IS NULL
+			GROUP BY `name`
+		) as grouped WHERE (created_at < '...' OR (created_at = '...' AND name > 'payments'))
+		ORDER BY created_at DESC, name LIMIT 10
+	*/
+	// the window is computed over all the groups before the position and LIMIT apply,
+	// so every row carries the total number of services.
+	grouped := r.search(search).
+		Select("name, COUNT(*) as count, COUNT(*) OVER () as total, " +
+			"MAX(created_at) as created_at, MAX(updated_at) as updated_at, MAX(version_key) as version_key").
+		Group("name")
+	query := r.db.Table("(?) as grouped", grouped).Limit(page.Limit)
+	switch {
+	case page.After != nil:
+		condition, args := sort.after(*page.After, false)
+		query = query.Where(condition, args...).Clauses(sort.orderBy(false))
+	case page.Before != nil:
+		condition, args := sort.after(*page.Before, true)
+		query = query.Where(condition, args...).Clauses(sort.orderBy(true))
+	default:
+		query = query.Offset(page.Offset).Clauses(sort.orderBy(false))
+	}
+	var rows []struct {
+		ServiceCount
+		Total int64
+	}
+	result := query.Scan(&rows)
+
+	if result.Error != nil {
+		log.Error("error in fetching service with pagination", "error", result.Error.Error())
+		return []ServiceCount{}, 0, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return []ServiceCount{}, 0, errors.New(customerrors.ErrServiceNotFound)
+	}
+	results := make([]ServiceCount, len(rows))
+	for i, row := range rows {
+		results[i] = row.ServiceCount
+	}
+	if page.Before != nil {
+		// walked back from the position, the page is put back in order.
+		slices.Reverse(results)
+	}
+	return results, rows[0].Total, nil
+}
+
+func (r *GormServiceRepository) GetSummaries(names []string) ([]ServiceSummary, error) {
+	log.Debug("fetching service summaries", "names", names)
+	var output []ServiceSummary
+	if len(names) == 0 {
+		return output, nil
+	}
+	// versions are ranked per service, the current version first and then by precedence.
+	ranked := r.db.Model(&Service{}).
+		Select("services.*, COUNT(*) OVER (PARTITION BY services.name) as total_versions, "+
+			"ROW_NUMBER() OVER (PARTITION BY services.name ORDER BY COALESCE(services.version_key = current_versions.version_key, 0) DESC, services.version_key DESC) as version_rank").
+		Joins("LEFT JOIN current_versions ON current_versions.name = services.name").
+		Where("services.name IN ?", names)
+	result := r.db.Table("(?) as ranked", ranked).Where("version_rank = 1").Scan(&output)
+	if result.Error != nil {
+		log.Error("error in fetching service summaries", "names", names, "error", result.Error.Error())
+		return output, result.Error
+	}
+	if len(output) == 0 {
+		return output, nil
+	}
+	// Scan leaves the associations out, the labels of all the summaries are fetched at once.
+	ids := make([]uint, len(output))
+	byID := map[uint]*ServiceSummary{}
+	for i := range output {
+		ids[i] = output[i].ID
+		byID[output[i].ID] = &output[i]
+	}
+	var all []Label
+	if err := r.db.Where("service_id IN ?", ids).Order("service_id, `key`").Find(&all).Error; err != nil {
+		log.Error("error in fetching service summary labels", "names", names, "error", err.Error())
+		return output, err
+	}
+	for _, l := range all {
+		summary := byID[l.ServiceID]
+		summary.Labels = append(summary.Labels, l)
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) GetFacetCounts(search ServiceSearch) ([]FacetCount, error) {
+	log.Debug("fetching facet counts", "query", search.Query, "match", search.Match, "fields", search.Fields)
+	/*
+		(SELECT 'label' as facet, service_labels.`key` as `key`, service_labels.`value` as value, COUNT(DISTINCT services.name) as count
+			FROM `services` JOIN service_labels ON service_labels.service_id = services.id
+			WHERE (...) AND `services`.`deleted_at` IS NULL GROUP BY service_labels.`key`, service_labels.`value`)
+		UNION ALL
+		(SELECT 'active' as facet, '' as `key`, IF(is_active, 'true', 'false') as value, COUNT(DISTINCT services.name) as count
+			FROM `services` WHERE (...) AND `services`.`deleted_at` IS NULL GROUP BY value)
+		UNION ALL ...
+	*/
+	// a service is counted once per value, whichever of its matching versions carry it.
+	const count = "COUNT(DISTINCT services.name) as count"
+	labels := r.search(search).
+		Select("? as facet, service_labels.`key` as `key`, service_labels.`value` as value, "+count, FacetLabel).
+		Joins("JOIN service_labels ON service_labels.service_id = services.id").
+		Group("service_labels.`key`, service_labels.`value`")
+	active := r.search(search).
+		Select("? as facet, '' as `key`, IF(services.is_active, 'true', 'false') as value, "+count, FacetActive).
+		Group("value")
+	owners := r.search(search).
+		Select("? as facet, '' as `key`, COALESCE(services.owner, '') as value, "+count, FacetOwner).
+		Group("value")
+	total := r.search(search).
+		Select("? as facet, '' as `key`, '' as value, "+count, FacetTotal)
+	var output []FacetCount
+	result := r.db.Raw("? UNION ALL ? UNION ALL ? UNION ALL ?", labels, active, owners, total).Scan(&output)
+	if result.Error != nil {
+		log.Error("error in fetching facet counts", "error", result.Error.Error())
+		return output, result.Error
+	}
+	return output, nil
+}
+
+// search returns a query on the live service versions matching the search.
+func (r *GormServiceRepository) search(search ServiceSearch) *gorm.DB {
+	db := r.db.Model(&Service{})
+	if condition, args := search.condition(); condition != "" {
+		db = db.Where(condition, args...)
+	}
+	return db
+}
+
+func (r *GormServiceRepository) GetByNameAndVersion(name string, version string) (Service, error) {
+	log.Debug("fetching service with name and version", "name", name, "version", version)
+	var output Service
+	result := r.db.Preload("Labels").Preload("Dependencies").Where("name = ? and version = ?", name, version).Find(&output)
+	if result.Error != nil {
+		log.Error("error in fetching service with name and version", "name", name, "version", version, "error", result.Error)
+		return output, result.Error
+	}
+	if result.RowsAffected == 0 {
+		log.Warn("service not found", "name", name, "version", version)
+		return output, errors.New(customerrors.ErrServiceWithVersionNotFound)
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) UpdateByNameAndVersion(s *Service, fields ...string) error {
+	log.Debug("updating service with name and version", "name", s.Name, "version", s.Version)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// the revision is bumped first, it locks the row so concurrent editors are serialized on it.
+		bump := tx.Model(&Service{}).Where("name = ? and version = ?", s.Name, s.Version)
+		if s.Revision != 0 {
+			bump = bump.Where("revision = ?", s.Revision)
+		}
+		result := bump.UpdateColumn("revision", gorm.Expr("revision + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 && s.Revision != 0 {
+			return errors.New(customerrors.ErrRevisionMismatch)
+		}
+		if err := tx.Model(&Service{}).Where("name = ? and version = ?", s.Name, s.Version).Pluck("revision", &s.Revision).Error; err != nil {
+			return err
+		}
+		// associations are left alone by Updates, the labels and dependencies are replaced below.
+		result = tx.Model(&s).Omit(clause.Associations, "revision").Where("name = ? and version = ?", s.Name, s.Version).Updates(s)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(fields) > 0 {
+			// Select writes the fields named even when they are zero.
+			if err := tx.Model(&s).Select(fields).Where("name = ? and version = ?", s.Name, s.Version).Updates(s).Error; err != nil {
+				return err
+			}
+		}
+		if s.Lifecycle != "" {
+			// Updates skips the false is_active and the cleared sunset date a lifecycle change may come with.
+			derived := map[string]interface{}{"is_active": s.IsActive}
+			if IsActiveLifecycle(s.Lifecycle) {
+				derived["deprecated_at"] = nil
+				derived["sunset_date"] = nil
+				derived["successor"] = nil
+			}
+			if err := tx.Model(&Service{}).Where("name = ? and version = ?", s.Name, s.Version).Updates(derived).Error; err != nil {
+				return err
+			}
+		}
+		if s.Labels == nil && s.Dependencies == nil {
+			return nil
+		}
+		var id uint
+		if err := tx.Model(&Service{}).Where("name = ? and version = ?", s.Name, s.Version).Pluck("id", &id).Error; err != nil {
+			return err
+		}
+		if s.Labels != nil {
+			// Updates skips the tags when they are cleared along with the labels.
+			if err := tx.Model(&Service{}).Where("id = ?", id).Update("tags", s.Tags).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("service_id = ?", id).Delete(&Label{}).Error; err != nil {
+				return err
+			}
+			for i := range s.Labels {
+				s.Labels[i].ID = 0
+				s.Labels[i].ServiceID = id
+			}
+			if len(s.Labels) > 0 {
+				if err := tx.Create(&s.Labels).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if s.Dependencies != nil {
+			if err := tx.Where("service_id = ?", id).Delete(&Dependency{}).Error; err != nil {
+				return err
+			}
+			for i := range s.Dependencies {
+				s.Dependencies[i].ID = 0
+				s.Dependencies[i].ServiceID = id
+			}
+			if len(s.Dependencies) > 0 {
+				return tx.Create(&s.Dependencies).Error
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("error in updating service with name and version", "name", s.Name, "version", s.Version, "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r *GormServiceRepository) DeleteByName(name string) error {
+	log.Debug("deleting service", "name", name)
+	//add Unscoped() for hard delete
+	result := r.db.Delete(&Service{}, "name = ?", name)
+	if result.Error != nil {
+		log.Error("error in deleting service", "name", name, "error", result.Error.Error())
+		return result.Error
+	}
+	return nil
+}
+
+func (r *GormServiceRepository) DeleteByNameAndVersion(name string, version string) error {
+	log.Debug("deleting service version", "name", name, "version", version)
+	result := r.db.Delete(&Service{}, "name = ? and version = ?", name, version)
+	if result.Error != nil {
+		log.Error("error in deleting service version", "name", name, "version", version, "error", result.Error.Error())
+		return result.Error
+	}
+	return nil
+}
+
+func (r *GormServiceRepository) ListDeleted() ([]Service, error) {
+	log.Debug("fetching deleted services")
+	var output []Service
+	result := r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("name, version_key").Find(&output)
+	if result.Error != nil {
+		log.Error("error in listing deleted services", "error", result.Error.Error())
+		return output, result.Error
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) GetDeletedByName(name string) ([]Service, error) {
+	log.Debug("fetching deleted service by name", "service", name)
+	var output []Service
+	result := r.db.Unscoped().Where("name = ? and deleted_at IS NOT NULL", name).Order("version_key").Find(&output)
+	if result.Error != nil {
+		log.Error("error in getting deleted service by name", "name", name, "error", result.Error.Error())
+		return output, result.Error
+	}
+	if result.RowsAffected == 0 {
+		log.Warn("deleted service not found", "name", name)
+		return output, errors.New(customerrors.ErrDeletedServiceNotFound)
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) Restore(ids []uint) error {
+	log.Debug("restoring services", "ids", ids)
+	result := r.db.Unscoped().Model(&Service{}).Where("id IN ?", ids).Update("deleted_at", nil)
+	if result.Error != nil {
+		log.Error("error in restoring services", "ids", ids, "error", result.Error.Error())
+		if isMysqlError(result.Error, mysqlErrDuplicateEntry) {
+			return errors.New(customerrors.ErrServiceVersionExists)
+		}
+		return result.Error
+	}
+	return nil
+}
+
+func (r *GormServiceRepository) ListDeletedBefore(before time.Time) ([]Service, error) {
+	log.Debug("fetching services deleted before", "before", before)
+	var output []Service
+	result := r.db.Unscoped().Where("deleted_at < ?", before).Order("deleted_at, id").Find(&output)
+	if result.Error != nil {
+		log.Error("error in listing deleted services", "before", before, "error", result.Error.Error())
+		return output, result.Error
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) Purge(before time.Time, limit int) ([]Service, error) {
+	log.Debug("purging services deleted before", "before", before, "limit", limit)
+	var purged []Service
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// gorm ignores LIMIT on DELETE, the batch is selected first.
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Where("deleted_at < ?", before).
+			Order("deleted_at, id").Limit(limit).Find(&purged).Error; err != nil {
+			return err
+		}
+		if len(purged) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(purged))
+		for i, s := range purged {
+			ids[i] = s.ID
+		}
+		if err := tx.Unscoped().Where("id IN ?", ids).Delete(&Service{}).Error; err != nil {
+			return err
+		}
+		names := tx.Unscoped().Model(&Service{}).Distinct("name").Where("name IS NOT NULL")
+		return tx.Where("name NOT IN (?)", names).Delete(&CurrentVersion{}).Error
+	})
+	if err != nil {
+		log.Error("error in purging deleted services", "before", before, "error", err.Error())
+		return nil, err
+	}
+	return purged, nil
+}
+
+func (r *GormServiceRepository) GetLiveAt(names []string, at time.Time) ([]Service, error) {
+	log.Debug("fetching services live at", "names", names, "at", at)
+	var output []Service
+	result := r.liveAt(at).Preload("Labels").Preload("Dependencies").Where("name IN ?", names).
+		Order("name, version_key").Find(&output)
+	if result.Error != nil {
+		log.Error("error in fetching services live at", "names", names, "at", at, "error", result.Error.Error())
+		return output, result.Error
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) GetLiveNamesAt(at time.Time, desc bool, page ServicePage) ([]string, int64, error) {
+	log.Debug("fetching names of services live at", "at", at, "limit", page.Limit, "offset", page.Offset)
+	var total int64
+	if err := r.liveAt(at).Model(&Service{}).Distinct("name").Count(&total).Error; err != nil {
+		log.Error("error in counting services live at", "at", at, "error", err.Error())
+		return nil, 0, err
+	}
+	names := []string{}
+	order := clause.OrderByColumn{Column: clause.Column{Name: "name"}, Desc: desc}
+	if err := r.liveAt(at).Model(&Service{}).Distinct("name").Order(order).
+		Offset(page.Offset).Limit(page.Limit).Pluck("name", &names).Error; err != nil {
+		log.Error("error in fetching names of services live at", "at", at, "error", err.Error())
+		return nil, 0, err
+	}
+	return names, total, nil
+}
+
+// liveAt selects the service versions created at or before the time and deleted after it, if at all.
+func (r *GormServiceRepository) liveAt(at time.Time) *gorm.DB {
+	return r.db.Unscoped().Where("created_at <= ? AND (deleted_at IS NULL OR deleted_at > ?)", at, at)
+}
+
+func (r *GormServiceRepository) GetDeprecations(before time.Time) ([]Service, error) {
+	log.Debug("fetching deprecations", "before", before)
+	var output []Service
+	result := r.db.Where("lifecycle = ? AND sunset_date <= ?", LifecycleDeprecated, before).
+		Order("sunset_date, name, version_key").Find(&output)
+	if result.Error != nil {
+		log.Error("error in fetching deprecations", "before", before, "error", result.Error.Error())
+		return output, result.Error
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) GetDependencyEdges() ([]DependencyEdge, error) {
+	log.Debug("fetching dependency edges")
+	return r.dependencyEdges(r.db)
+}
+
+func (r *GormServiceRepository) LockDependencyEdges() ([]DependencyEdge, error) {
+	log.Debug("locking dependency edges")
+	// scanning the whole table locks every row and the gaps between them, so other writers of dependencies
+	// wait for this transaction. The edges are then read with a locking read too, a plain one would see the
+	// snapshot the transaction started with rather than what the writers waited for committed.
+	var ids []uint
+	result := r.db.Model(&Dependency{}).Clauses(clause.Locking{Strength: "UPDATE"}).Pluck("id", &ids)
+	if result.Error != nil {
+		log.Error("error in locking dependency edges", "error", result.Error.Error())
+		return nil, result.Error
+	}
+	return r.dependencyEdges(r.db.Clauses(clause.Locking{Strength: "SHARE"}))
+}
+
+func (r *GormServiceRepository) dependencyEdges(db *gorm.DB) ([]DependencyEdge, error) {
+	var output []DependencyEdge
+	result := db.Model(&Dependency{}).
+		Select("services.name as service, services.version, service_dependencies.name as dependency, " +
+			"service_dependencies.version_constraint as `constraint`, " +
+			"COALESCE(services.version_key = current_versions.version_key, 0) as `current`").
+		Joins("JOIN services ON services.id = service_dependencies.service_id AND services.deleted_at IS NULL").
+		Joins("LEFT JOIN current_versions ON current_versions.name = services.name").
+		Order("services.name, services.version_key, service_dependencies.name").
+		Scan(&output)
+	if result.Error != nil {
+		log.Error("error in fetching dependency edges", "error", result.Error.Error())
+		return output, result.Error
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) GetCurrentVersion(name string) (CurrentVersion, error) {
+	log.Debug("fetching current version", "name", name)
+	var output CurrentVersion
+	result := r.db.Where("name = ?", name).Limit(1).Find(&output)
+	if result.Error != nil {
+		log.Error("error in fetching current version", "name", name, "error", result.Error.Error())
+		return output, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return output, errors.New(customerrors.ErrCurrentVersionNotFound)
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) SetCurrentVersion(c *CurrentVersion) error {
+	log.Debug("setting current version", "name", c.Name, "version", c.Version)
+	result := r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"version", "version_key", "updated_at"}),
+	}).Create(c)
+	if result.Error != nil {
+		log.Error("error in setting current version", "name", c.Name, "version", c.Version, "error", result.Error.Error())
+		return result.Error
+	}
+	return nil
+}
+
+func (r *GormServiceRepository) AdvanceCurrentVersion(c *CurrentVersion) error {
+	log.Debug("advancing current version", "name", c.Name, "version", c.Version)
+	// assignments are applied left to right, version is compared against the old version_key.
+	result := r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "version"}, Value: gorm.Expr("IF(VALUES(version_key) > version_key, VALUES(version), version)")},
+			{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("IF(VALUES(version_key) > version_key, VALUES(updated_at), updated_at)")},
+			{Column: clause.Column{Name: "version_key"}, Value: gorm.Expr("GREATEST(version_key, VALUES(version_key))")},
+		},
+	}).Create(c)
+	if result.Error != nil {
+		log.Error("error in advancing current version", "name", c.Name, "version", c.Version, "error", result.Error.Error())
+		return result.Error
+	}
+	return nil
+}
+
+func (r *GormServiceRepository) AddAuditEvent(e *AuditEvent) error {
+	log.Debug("adding audit event", "action", e.Action, "service", e.Service, "version", e.Version)
+	result := r.db.Create(e)
+	if result.Error != nil {
+		log.Error("error in adding audit event", "action", e.Action, "service", e.Service, "error", result.Error.Error())
+		return result.Error
+	}
+	return nil
+}
+
+func (r *GormServiceRepository) GetAuditEvents(search AuditSearch) ([]AuditEvent, error) {
+	log.Debug("fetching audit events", "service", search.Service, "actor", search.Actor)
+	output := []AuditEvent{}
+	result := r.auditQuery(search).Order("id").Limit(search.Limit).Find(&output)
+	if result.Error != nil {
+		log.Error("error in fetching audit events", "service", search.Service, "actor", search.Actor, "error", result.Error.Error())
+		return output, result.Error
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) GetFirstAuditEvents(search AuditSearch) ([]AuditEvent, error) {
+	log.Debug("fetching first audit events of the versions", "services", search.Services, "actions", search.Actions)
+	output := []AuditEvent{}
+	first := r.auditQuery(search).Model(&AuditEvent{}).Select("MIN(id)").Group("service, version")
+	result := r.db.Where("id IN (?)", first).Order("id").Find(&output)
+	if result.Error != nil {
+		log.Error("error in fetching first audit events of the versions", "services", search.Services, "error", result.Error.Error())
+		return output, result.Error
+	}
+	return output, nil
+}
+
+func (r *GormServiceRepository) GetLastAuditEvents(search AuditSearch) ([]AuditEvent, error) {
+	log.Debug("fetching last audit events of the services", "services", search.Services, "actions", search.Actions)
+	output := []AuditEvent{}
+	last := r.auditQuery(search).Model(&AuditEvent{}).Select("MAX(id)").Group("service")
+	result := r.db.Where("id IN (?)", last).Order("id").Find(&output)
+	if result.Error != nil {
+		log.Error("error in fetching last audit events of the services", "services", search.Services, "error", result.Error.Error())
+		return output, result.Error
+	}
+	return output, nil
+}
+
+// auditQuery selects the audit events matching the search, the limit aside.
+func (r *GormServiceRepository) auditQuery(search AuditSearch) *gorm.DB {
+	query := r.db.Where("id > ?", search.SinceID)
+	if search.Service != "" {
+		query = query.Where("service = ?", search.Service)
+	}
+	if search.Team != "" {
+		query = query.Where("team = ?", search.Team)
+	}
+	if len(search.Services) > 0 {
+		query = query.Where("service IN ?", search.Services)
+	}
+	if search.Actor != "" {
+		query = query.Where("actor = ?", search.Actor)
+	}
+	if len(search.Actions) > 0 {
+		query = query.Where("action IN ?", search.Actions)
+	}
+	if !search.From.IsZero() {
+		query = query.Where("created_at >= ?", search.From)
+	}
+	if !search.To.IsZero() {
+		query = query.Where("created_at < ?", search.To)
+	}
+	return query
+}
+
+func (r *GormServiceRepository) Transaction(fn func(repo ServiceRepository) error) error {
+	for attempt := 1; ; attempt++ {
+		// nested transactions, like the one of AddNextVersion, become savepoints.
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			return fn(&GormServiceRepository{db: tx, inTransaction: true})
+		})
+		// a deadlock rolls back the whole transaction, it is run again unless it is nested in another one.
+		if err == nil || r.inTransaction || !isMysqlError(err, mysqlErrLockDeadlock) {
+			return err
+		}
+		if attempt == maxTransactionAttempts {
+			log.Error("giving up on transaction rolled back by deadlocks", "attempts", attempt, "error", err.Error())
+			return err
+		}
+		log.Warn("transaction rolled back by a deadlock, retrying", "attempt", attempt, "error", err.Error())
+		time.Sleep(time.Duration(attempt) * 10 * time.Millisecond)
+	}
+}
+
+// isMysqlError reports whether err is a mysql error with one of the given numbers.
+func isMysqlError(err error, numbers ...uint16) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	for _, n := range numbers {
+		if mysqlErr.Number == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"errors"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"gorm.io/gorm"
+	"time"
+)
+
+// expiredKeysBatchSize is the number of expired keys deleted when a key is reserved.
+const expiredKeysBatchSize = 100
+
+type GormIdempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormIdempotencyRepository(db *gorm.DB) *GormIdempotencyRepository {
+	return &GormIdempotencyRepository{db: db}
+}
+
+func (r *GormIdempotencyRepository) Reserve(k *IdempotencyKey) (IdempotencyKey, bool, error) {
+	log.Debug("reserving idempotency key", "key", k.Key)
+	now := time.Now()
+	// an expired key is reserved again, the other expired keys are deleted a batch at a time along the way.
+	if err := r.db.Where("idempotency_key = ? AND expires_at <= ?", k.Key, now).Delete(&IdempotencyKey{}).Error; err != nil {
+		log.Error("error in deleting expired idempotency key", "key", k.Key, "error", err.Error())
+		return IdempotencyKey{}, false, err
+	}
+	// gorm ignores LIMIT on DELETE, the batch is selected first.
+	var ids []uint
+	if err := r.db.Model(&IdempotencyKey{}).Where("expires_at <= ?", now).
+		Order("expires_at").Limit(expiredKeysBatchSize).Pluck("id", &ids).Error; err != nil {
+		log.Error("error in selecting expired idempotency keys", "error", err.Error())
+		return IdempotencyKey{}, false, err
+	}
+	if len(ids) > 0 {
+		if err := r.db.Where("id IN ?", ids).Delete(&IdempotencyKey{}).Error; err != nil {
+			log.Error("error in deleting expired idempotency keys", "error", err.Error())
+			return IdempotencyKey{}, false, err
+		}
+	}
+
+	result := r.db.Create(k)
+	if result.Error == nil {
+		return IdempotencyKey{}, true, nil
+	}
+	if !isMysqlError(result.Error, mysqlErrDuplicateEntry) {
+		log.Error("error in reserving idempotency key", "key", k.Key, "error", result.Error.Error())
+		return IdempotencyKey{}, false, result.Error
+	}
+	var stored IdempotencyKey
+	err := r.db.Where("idempotency_key = ?", k.Key).Take(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// the key was released in between.
+		k.ID = 0
+		return r.Reserve(k)
+	}
+	if err != nil {
+		log.Error("error in fetching idempotency key", "key", k.Key, "error", err.Error())
+		return IdempotencyKey{}, false, err
+	}
+	return stored, false, nil
+}
+
+func (r *GormIdempotencyRepository) Complete(k *IdempotencyKey) error {
+	log.Debug("completing idempotency key", "key", k.Key, "status", k.StatusCode)
+	// the id tells the reservation apart from one taking it over once its lease ended.
+	result := r.db.Model(&IdempotencyKey{}).Where("id = ?", k.ID).
+		Updates(map[string]interface{}{"expires_at": k.ExpiresAt, "status_code": k.StatusCode, "header": k.Header, "body": k.Body})
+	if result.Error != nil {
+		log.Error("error in completing idempotency key", "key", k.Key, "error", result.Error.Error())
+		return result.Error
+	}
+	return nil
+}
+
+func (r *GormIdempotencyRepository) Release(k *IdempotencyKey) error {
+	log.Debug("releasing idempotency key", "key", k.Key)
+	result := r.db.Where("id = ?", k.ID).Delete(&IdempotencyKey{})
+	if result.Error != nil {
+		log.Error("error in releasing idempotency key", "key", k.Key, "error", result.Error.Error())
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type GormTeamRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTeamRepository(db *gorm.DB) *GormTeamRepository {
+	return &GormTeamRepository{db: db}
+}
+
+func (r *GormTeamRepository) Add(t *Team) error {
+	log.Debug("adding team", "team", t.Name)
+	result := r.db.Create(t)
+	if result.Error != nil {
+		log.Error("error in adding team", "team", t.Name, "error", result.Error.Error())
+		if isMysqlError(result.Error, mysqlErrDuplicateEntry) {
+			return errors.New(customerrors.ErrTeamFoundWithSameName)
+		}
+		return result.Error
+	}
+	return nil
+}
+
+func (r *GormTeamRepository) List() ([]Team, error) {
+	log.Debug("fetching all teams")
+	var output []Team
+	result := r.db.Preload("Owners").Order("name").Find(&output)
+	if result.Error != nil {
+		log.Error("error in listing teams", "error", result.Error.Error())
+		return output, result.Error
+	}
+	return output, nil
+}
+
+func (r *GormTeamRepository) GetByName(name string) (Team, error) {
+	log.Debug("fetching team by name", "team", name)
+	var output Team
+	result := r.db.Preload("Owners").Where("name = ?", name).Find(&output)
+	if result.Error != nil {
+		log.Error("error in fetching team by name", "team", name, "error", result.Error.Error())
+		return output, result.Error
+	}
+	if result.RowsAffected == 0 {
+		log.Warn("team not found", "team", name)
+		return output, errors.New(customerrors.ErrTeamNotFound)
+	}
+	return output, nil
+}
+
+func (r *GormTeamRepository) Update(t *Team) error {
+	log.Debug("updating team", "team", t.Name)
+	result := r.db.Model(&Team{}).Where("name = ?", t.Name).Update("description", t.Description)
+	if result.Error != nil {
+		log.Error("error in updating team", "team", t.Name, "error", result.Error.Error())
+		return result.Error
+	}
+	return nil
+}
+
+func (r *GormTeamRepository) Delete(name string) error {
+	log.Debug("deleting team", "team", name)
+	// owners are deleted by the foreign key and the services the team owned are left without an owner.
+	result := r.db.Where("name = ?", name).Delete(&Team{})
+	if result.Error != nil {
+		log.Error("error in deleting team", "team", name, "error", result.Error.Error())
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(customerrors.ErrTeamNotFound)
+	}
+	return nil
+}
+
+func (r *GormTeamRepository) AddOwner(o *Owner) error {
+	log.Debug("adding owner", "team_id", o.TeamID, "email", o.Email)
+	result := r.db.Create(o)
+	if result.Error != nil {
+		log.Error("error in adding owner", "team_id", o.TeamID, "email", o.Email, "error", result.Error.Error())
+		if isMysqlError(result.Error, mysqlErrDuplicateEntry) {
+			return errors.New(customerrors.ErrOwnerExists)
+		}
+		return result.Error
+	}
+	return nil
+}
+
+func (r *GormTeamRepository) DeleteOwner(teamID uint, email string) error {
+	log.Debug("deleting owner", "team_id", teamID, "email", email)
+	result := r.db.Where("team_id = ? AND email = ?", teamID, email).Delete(&Owner{})
+	if result.Error != nil {
+		log.Error("error in deleting owner", "team_id", teamID, "email", email, "error", result.Error.Error())
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(customerrors.ErrOwnerNotFound)
+	}
+	return nil
+}
+
+func (r *GormTeamRepository) Transaction(repo ServiceRepository, fn func(teams TeamRepository) error) error {
+	tx, ok := repo.(*GormServiceRepository)
+	if !ok {
+		return fmt.Errorf("can not write teams in a transaction of %T", repo)
+	}
+	return fn(&GormTeamRepository{db: tx.db})
+}
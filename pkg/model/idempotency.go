@@ -0,0 +1,36 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// IdempotencyKey is a request made with an Idempotency-Key header and the response it got, the response is
+// replayed when the request is retried with the same key until the key expires.
+type IdempotencyKey struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	// ExpiresAt is when the lease of a request in progress ends, or the stored response expires once it completed.
+	ExpiresAt time.Time
+	// Key is the Idempotency-Key scoped to the method, path and caller of the request, hashed.
+	Key string `gorm:"column:idempotency_key"`
+	// Fingerprint identifies the request by its body, a retry has the same one.
+	Fingerprint string
+	// StatusCode is 0 while the request is in progress.
+	StatusCode int
+	Header     json.RawMessage
+	Body       []byte
+}
+
+type IdempotencyRepository interface {
+	// Reserve stores the key of a request about to be processed and reports whether it did, when the key is
+	// already stored the stored key is returned instead. Expired keys, including the reservations of requests
+	// which outlived their lease, are deleted and can be reserved again.
+	Reserve(k *IdempotencyKey) (IdempotencyKey, bool, error)
+	// Complete stores the response of the request of a reserved key along with when it expires. A reservation
+	// taken over by another request is left as it is.
+	Complete(k *IdempotencyKey) error
+	// Release deletes a reserved key, so the request can be retried. A reservation taken over by another request
+	// is left as it is.
+	Release(k *IdempotencyKey) error
+}
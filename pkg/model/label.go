@@ -0,0 +1,238 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// label keys and values follow the Kubernetes syntax, a key may be prefixed with a DNS subdomain and a slash.
+// migration 013 backfills the labels with the same expressions.
+var (
+	labelKey   = regexp.MustCompile(`^([a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?/)?[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`)
+	labelValue = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?)?$`)
+)
+
+// Label is a key=value pair of a service version, a label without a value is a plain tag.
+type Label struct {
+	ID        uint `gorm:"primaryKey"`
+	ServiceID uint
+	Key       string
+	Value     string
+	CreatedAt time.Time
+}
+
+func (Label) TableName() string {
+	return "service_labels"
+}
+
+// Labels are the labels of a service version ordered by key, clients read and write them as a JSON object.
+type Labels []Label
+
+// NewLabels returns the labels of the map ordered by key.
+func NewLabels(m map[string]string) Labels {
+	labels := make(Labels, 0, len(m))
+	for k, v := range m {
+		labels = append(labels, Label{Key: k, Value: v})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Key < labels[j].Key })
+	return labels
+}
+
+// Map returns the labels keyed by key.
+func (l Labels) Map() map[string]string {
+	m := make(map[string]string, len(l))
+	for _, label := range l {
+		m[label.Key] = label.Value
+	}
+	return m
+}
+
+// Tags renders the labels as the comma separated tags of old clients, key=value or the key alone for a plain tag.
+func (l Labels) Tags() string {
+	tags := make([]string, 0, len(l))
+	for _, label := range l {
+		if label.Value == "" {
+			tags = append(tags, label.Key)
+			continue
+		}
+		tags = append(tags, label.Key+"="+label.Value)
+	}
+	return strings.Join(tags, ",")
+}
+
+// Validate checks the keys and values follow the Kubernetes label syntax.
+func (l Labels) Validate() error {
+	for _, label := range l {
+		if !labelKey.MatchString(label.Key) {
+			return fmt.Errorf("invalid label key %q", label.Key)
+		}
+		if !labelValue.MatchString(label.Value) {
+			return fmt.Errorf("invalid value %q of label %q", label.Value, label.Key)
+		}
+	}
+	return nil
+}
+
+func (l Labels) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Map())
+}
+
+func (l *Labels) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*l = NewLabels(m)
+	return nil
+}
+
+// label selector operators.
+const (
+	SelectorIn           = "in"
+	SelectorNotIn        = "notin"
+	SelectorExists       = "exists"
+	SelectorDoesNotExist = "!"
+)
+
+// LabelRequirement is a single requirement of a LabelSelector.
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	// Values are the values of the key the In and NotIn operators match.
+	Values []string
+}
+
+// LabelSelector selects service versions by their labels, every requirement has to match.
+type LabelSelector []LabelRequirement
+
+// setRequirement is a requirement on a set of values, key in (a,b) or key notin (a,b).
+var setRequirement = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+
+// ParseLabelSelector parses a selector in the Kubernetes syntax, a comma separated list of requirements:
+//
+//	key=value, key==value, key!=value, key in (a,b), key notin (a,b), key, !key
+//
+// As in Kubernetes, key!=value and key notin (a,b) also match the versions without the key.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var s LabelSelector
+	if strings.TrimSpace(selector) == "" {
+		return s, nil
+	}
+	for _, requirement := range splitRequirements(selector) {
+		r, err := parseRequirement(strings.TrimSpace(requirement))
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, r)
+	}
+	return s, nil
+}
+
+// splitRequirements splits the selector on the commas which are not within the parentheses of a set.
+func splitRequirements(selector string) []string {
+	var requirements []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				requirements = append(requirements, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(requirements, selector[start:])
+}
+
+func parseRequirement(requirement string) (LabelRequirement, error) {
+	var r LabelRequirement
+	switch {
+	case requirement == "":
+		return r, fmt.Errorf("empty label selector requirement")
+	case strings.HasPrefix(requirement, "!"):
+		r = LabelRequirement{Key: strings.TrimSpace(requirement[1:]), Operator: SelectorDoesNotExist}
+	case setRequirement.MatchString(requirement):
+		m := setRequirement.FindStringSubmatch(requirement)
+		r = LabelRequirement{Key: m[1], Operator: m[2]}
+		for _, v := range strings.Split(m[3], ",") {
+			r.Values = append(r.Values, strings.TrimSpace(v))
+		}
+	case strings.Contains(requirement, "!="):
+		key, value, _ := strings.Cut(requirement, "!=")
+		r = LabelRequirement{Key: strings.TrimSpace(key), Operator: SelectorNotIn, Values: []string{strings.TrimSpace(value)}}
+	case strings.Contains(requirement, "="):
+		key, value, _ := strings.Cut(requirement, "=")
+		value = strings.TrimPrefix(value, "=")
+		r = LabelRequirement{Key: strings.TrimSpace(key), Operator: SelectorIn, Values: []string{strings.TrimSpace(value)}}
+	default:
+		r = LabelRequirement{Key: requirement, Operator: SelectorExists}
+	}
+	if !labelKey.MatchString(r.Key) {
+		return r, fmt.Errorf("invalid label key %q in requirement %q", r.Key, requirement)
+	}
+	for _, v := range r.Values {
+		if !labelValue.MatchString(v) {
+			return r, fmt.Errorf("invalid label value %q in requirement %q", v, requirement)
+		}
+	}
+	return r, nil
+}
+
+// Matches reports whether the labels satisfy every requirement of the selector.
+func (s LabelSelector) Matches(labels Labels) bool {
+	m := labels.Map()
+	for _, r := range s {
+		value, ok := m[r.Key]
+		var matches bool
+		switch r.Operator {
+		case SelectorIn:
+			matches = ok && contains(r.Values, value)
+		case SelectorNotIn:
+			matches = !ok || !contains(r.Values, value)
+		case SelectorExists:
+			matches = ok
+		case SelectorDoesNotExist:
+			matches = !ok
+		}
+		if !matches {
+			return false
+		}
+	}
+	return true
+}
+
+// condition returns the SQL condition matching the selector on the services table, every requirement
+// is a correlated subquery on the labels of the version.
+func (s LabelSelector) condition() (string, []interface{}) {
+	const labelled = "EXISTS (SELECT 1 FROM service_labels WHERE service_labels.service_id = services.id AND service_labels.`key` = ?"
+	var conditions []string
+	var args []interface{}
+	for _, r := range s {
+		switch r.Operator {
+		case SelectorIn:
+			conditions = append(conditions, labelled+" AND service_labels.`value` IN ?)")
+			args = append(args, r.Key, r.Values)
+		case SelectorNotIn:
+			conditions = append(conditions, "NOT "+labelled+" AND service_labels.`value` IN ?)")
+			args = append(args, r.Key, r.Values)
+		case SelectorExists:
+			conditions = append(conditions, labelled+")")
+			args = append(args, r.Key)
+		case SelectorDoesNotExist:
+			conditions = append(conditions, "NOT "+labelled+")")
+			args = append(args, r.Key)
+		}
+	}
+	return strings.Join(conditions, " AND "), args
+}
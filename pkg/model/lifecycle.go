@@ -0,0 +1,21 @@
+package model
+
+// lifecycle states of a service version.
+const (
+	LifecycleExperimental = "experimental"
+	LifecycleProduction   = "production"
+	LifecycleDeprecated   = "deprecated"
+	LifecycleRetired      = "retired"
+)
+
+var lifecycles = []string{LifecycleExperimental, LifecycleProduction, LifecycleDeprecated, LifecycleRetired}
+
+// IsLifecycle reports whether s is a lifecycle state.
+func IsLifecycle(s string) bool {
+	return contains(lifecycles, s)
+}
+
+// IsActiveLifecycle reports whether versions in the lifecycle state are active, experimental and production ones are.
+func IsActiveLifecycle(lifecycle string) bool {
+	return lifecycle == LifecycleExperimental || lifecycle == LifecycleProduction
+}
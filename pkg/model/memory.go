@@ -0,0 +1,783 @@
+package model
+
+import (
+	"errors"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"github.com/suyog1pathak/services/pkg/semver"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemoryServiceRepository is an in process implementation of ServiceRepository.
+// Rows are soft deleted the same way GORM does it, by setting DeletedAt.
+// Transactions are serialized and rolled back by restoring the rows they started with, writes outside of a
+// transaction wait for it so a rollback only undoes its own writes.
+type MemoryServiceRepository struct {
+	*memoryRows
+	// inTransaction is set on the repository Transaction hands out, its writes are covered by the transaction.
+	inTransaction bool
+}
+
+// memoryRows are the rows of a MemoryServiceRepository, shared with the repositories its transactions hand out.
+type memoryRows struct {
+	// txMu is held by a transaction and by writes outside of one, it is taken before mu.
+	txMu            sync.Mutex
+	mu              sync.RWMutex
+	lastID          uint
+	services        []Service
+	currentVersions map[string]CurrentVersion
+	auditEvents     []AuditEvent
+}
+
+func NewMemoryServiceRepository() *MemoryServiceRepository {
+	return &MemoryServiceRepository{memoryRows: &memoryRows{currentVersions: map[string]CurrentVersion{}}}
+}
+
+// NewMemoryServiceRepositoryOf returns a repository holding the given rows and current version pointers as they are,
+// it is used to query a snapshot of the catalog.
+func NewMemoryServiceRepositoryOf(services []Service, currentVersions []CurrentVersion) *MemoryServiceRepository {
+	r := NewMemoryServiceRepository()
+	for _, s := range services {
+		r.lastID = max(r.lastID, s.ID)
+		r.services = append(r.services, s)
+	}
+	for _, c := range currentVersions {
+		r.currentVersions[c.Name] = c
+	}
+	return r
+}
+
+func (r *MemoryServiceRepository) Add(s *Service) error {
+	log.Debug("adding service", "service", s.Name)
+	defer r.write()()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.add(s)
+}
+
+func (r *MemoryServiceRepository) AddNextVersion(s *Service, bump string) error {
+	log.Debug("adding next service version", "service", s.Name)
+	defer r.write()()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.byName(s.Name)) == 0 {
+		return errors.New(customerrors.ErrServiceNotFound)
+	}
+	// soft deleted versions count, their numbers are not handed out again so they can still be restored.
+	var versions []Service
+	for _, v := range r.services {
+		if v.Name == s.Name {
+			versions = append(versions, v)
+		}
+	}
+	next, err := latestVersion(versions).Bump(bump)
+	if err != nil {
+		log.Warn("unable to bump the latest version", "service", s.Name, "error", err.Error())
+		return errors.New(customerrors.ErrInvalidVersion)
+	}
+	s.SetVersion(next)
+	return r.add(s)
+}
+
+func (r *MemoryServiceRepository) List() ([]Service, error) {
+	log.Debug("fetching all services")
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var output []Service
+	for _, s := range r.live() {
+		if s.IsActive {
+			output = append(output, s)
+		}
+	}
+	return output, nil
+}
+
+func (r *MemoryServiceRepository) GetByName(name string) ([]Service, error) {
+	log.Debug("fetching service by name", "service", name)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	output := r.byName(name)
+	if len(output) == 0 {
+		log.Warn("service not found", "name", name)
+		return output, errors.New(customerrors.ErrServiceNotFound)
+	}
+	return output, nil
+}
+
+func (r *MemoryServiceRepository) GetByNameCount(name string) (int64, error) {
+	log.Debug("fetching count service by name", "service", name)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return int64(len(r.byName(name))), nil
+}
+
+func (r *MemoryServiceRepository) GetServiceAndVersionCounts(search ServiceSearch, sort ServiceSort, page ServicePage) ([]ServiceCount, int64, error) {
+	log.Debug("fetching service and version counts", "query", search.Query, "match", search.Match, "fields", search.Fields,
+		"sort", sort.String(), "limit", page.Limit, "offset", page.Offset, "after", page.After != nil, "before", page.Before != nil)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groups := map[string]*ServiceCount{}
+	var counts []*ServiceCount
+	for _, s := range r.live() {
+		if !search.Matches(s) {
+			continue
+		}
+		c, ok := groups[s.Name]
+		if !ok {
+			c = &ServiceCount{Name: s.Name}
+			groups[s.Name] = c
+			counts = append(counts, c)
+		}
+		c.Count++
+		if s.CreatedAt.After(c.CreatedAt) {
+			c.CreatedAt = s.CreatedAt
+		}
+		if s.UpdatedAt.After(c.UpdatedAt) {
+			c.UpdatedAt = s.UpdatedAt
+		}
+		if s.VersionKey > c.VersionKey {
+			c.VersionKey = s.VersionKey
+		}
+	}
+	all := make([]ServiceCount, len(counts))
+	for i, c := range counts {
+		all[i] = *c
+	}
+	sort.sort(all)
+
+	from, to := page.Offset, page.Offset+page.Limit
+	switch {
+	case page.After != nil:
+		from = len(all)
+		for i, c := range all {
+			if sort.compare(c, *page.After) > 0 {
+				from = i
+				break
+			}
+		}
+		to = from + page.Limit
+	case page.Before != nil:
+		to = 0
+		for i, c := range all {
+			if sort.compare(c, *page.Before) < 0 {
+				to = i + 1
+			}
+		}
+		from = to - page.Limit
+	}
+	from, to = max(from, 0), min(to, len(all))
+	if from >= to {
+		return []ServiceCount{}, 0, errors.New(customerrors.ErrServiceNotFound)
+	}
+	return all[from:to], int64(len(all)), nil
+}
+
+func (r *MemoryServiceRepository) GetFacetCounts(search ServiceSearch) ([]FacetCount, error) {
+	log.Debug("fetching facet counts", "query", search.Query, "match", search.Match, "fields", search.Fields)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	// the services carrying each facet value, a service is counted once per value.
+	total := FacetCount{Facet: FacetTotal}
+	services := map[FacetCount]map[string]bool{total: {}}
+	order := []FacetCount{total}
+	count := func(facet FacetCount, name string) {
+		if _, ok := services[facet]; !ok {
+			services[facet] = map[string]bool{}
+			order = append(order, facet)
+		}
+		services[facet][name] = true
+	}
+	for _, s := range r.live() {
+		if !search.Matches(s) {
+			continue
+		}
+		for _, l := range s.Labels {
+			count(FacetCount{Facet: FacetLabel, Key: l.Key, Value: l.Value}, s.Name)
+		}
+		count(FacetCount{Facet: FacetActive, Value: strconv.FormatBool(s.IsActive)}, s.Name)
+		count(FacetCount{Facet: FacetOwner, Value: string(s.Owner)}, s.Name)
+		count(total, s.Name)
+	}
+	output := make([]FacetCount, len(order))
+	for i, facet := range order {
+		facet.Count = int64(len(services[facet]))
+		output[i] = facet
+	}
+	return output, nil
+}
+
+func (r *MemoryServiceRepository) GetSummaries(names []string) ([]ServiceSummary, error) {
+	log.Debug("fetching service summaries", "names", names)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var output []ServiceSummary
+	for _, name := range names {
+		versions := r.byName(name)
+		if len(versions) == 0 {
+			continue
+		}
+		summary := ServiceSummary{Service: versions[len(versions)-1], TotalVersions: len(versions)}
+		if current, ok := r.currentVersions[name]; ok {
+			for _, v := range versions {
+				if v.Version == current.Version {
+					summary.Service = v
+				}
+			}
+		}
+		output = append(output, summary)
+	}
+	return output, nil
+}
+
+func (r *MemoryServiceRepository) GetByNameAndVersion(name string, version string) (Service, error) {
+	log.Debug("fetching service with name and version", "name", name, "version", version)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, s := range r.live() {
+		if s.Name == name && s.Version == version {
+			return s, nil
+		}
+	}
+	log.Warn("service not found", "name", name, "version", version)
+	return Service{}, errors.New(customerrors.ErrServiceWithVersionNotFound)
+}
+
+func (r *MemoryServiceRepository) UpdateByNameAndVersion(s *Service, fields ...string) error {
+	log.Debug("updating service with name and version", "name", s.Name, "version", s.Version)
+	defer r.write()()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for i := range r.services {
+		row := &r.services[i]
+		if row.DeletedAt.Valid || row.Name != s.Name || row.Version != s.Version {
+			continue
+		}
+		if s.Revision != 0 && row.Revision != s.Revision {
+			return errors.New(customerrors.ErrRevisionMismatch)
+		}
+		row.Revision++
+		s.Revision = row.Revision
+		// same as GORM Updates with a struct, zero values are skipped.
+		if s.Description != "" {
+			row.Description = s.Description
+		}
+		if s.IsActive {
+			row.IsActive = s.IsActive
+		}
+		if s.Tags != "" {
+			row.Tags = s.Tags
+		}
+		if s.Owner != "" {
+			row.Owner = s.Owner
+		}
+		// same as GORM Select, the fields named are written even when they are zero.
+		for _, field := range fields {
+			reflect.ValueOf(row).Elem().FieldByName(field).Set(reflect.ValueOf(s).Elem().FieldByName(field))
+		}
+		if s.Lifecycle != "" {
+			row.Lifecycle = s.Lifecycle
+			row.IsActive = s.IsActive
+			if IsActiveLifecycle(s.Lifecycle) {
+				row.DeprecatedAt = nil
+				row.SunsetDate = nil
+				row.Successor = ""
+			}
+		}
+		if s.DeprecatedAt != nil {
+			deprecated := *s.DeprecatedAt
+			row.DeprecatedAt = &deprecated
+		}
+		if s.SunsetDate != nil {
+			sunset := *s.SunsetDate
+			row.SunsetDate = &sunset
+		}
+		if s.Successor != "" {
+			row.Successor = s.Successor
+		}
+		if s.Changelog != "" {
+			row.Changelog = s.Changelog
+		}
+		if s.Labels != nil {
+			row.Labels = labels(row.ID, s.Labels)
+			row.Tags = s.Tags
+		}
+		if s.Dependencies != nil {
+			row.Dependencies = dependencies(row.ID, s.Dependencies)
+		}
+		row.UpdatedAt = now
+		s.UpdatedAt = now
+	}
+	return nil
+}
+
+func (r *MemoryServiceRepository) DeleteByName(name string) error {
+	log.Debug("deleting service", "name", name)
+	defer r.write()()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for i := range r.services {
+		if !r.services[i].DeletedAt.Valid && r.services[i].Name == name {
+			r.services[i].DeletedAt.Time = now
+			r.services[i].DeletedAt.Valid = true
+		}
+	}
+	return nil
+}
+
+func (r *MemoryServiceRepository) DeleteByNameAndVersion(name string, version string) error {
+	log.Debug("deleting service version", "name", name, "version", version)
+	defer r.write()()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for i := range r.services {
+		if !r.services[i].DeletedAt.Valid && r.services[i].Name == name && r.services[i].Version == version {
+			r.services[i].DeletedAt.Time = now
+			r.services[i].DeletedAt.Valid = true
+		}
+	}
+	return nil
+}
+
+func (r *MemoryServiceRepository) ListDeleted() ([]Service, error) {
+	log.Debug("fetching deleted services")
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var output []Service
+	for _, s := range r.services {
+		if s.DeletedAt.Valid {
+			output = append(output, s)
+		}
+	}
+	sort.SliceStable(output, func(i, j int) bool {
+		if output[i].Name != output[j].Name {
+			return output[i].Name < output[j].Name
+		}
+		return output[i].SemVer().Less(output[j].SemVer())
+	})
+	return output, nil
+}
+
+func (r *MemoryServiceRepository) GetDeletedByName(name string) ([]Service, error) {
+	log.Debug("fetching deleted service by name", "service", name)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var output []Service
+	for _, s := range r.services {
+		if s.DeletedAt.Valid && s.Name == name {
+			output = append(output, s)
+		}
+	}
+	if len(output) == 0 {
+		log.Warn("deleted service not found", "name", name)
+		return output, errors.New(customerrors.ErrDeletedServiceNotFound)
+	}
+	sort.SliceStable(output, func(i, j int) bool { return output[i].SemVer().Less(output[j].SemVer()) })
+	return output, nil
+}
+
+func (r *MemoryServiceRepository) Restore(ids []uint) error {
+	log.Debug("restoring services", "ids", ids)
+	defer r.write()()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	restore := map[uint]bool{}
+	for _, id := range ids {
+		restore[id] = true
+	}
+	// check every row first, the update is all or nothing like the single UPDATE statement.
+	for _, s := range r.services {
+		if !restore[s.ID] || !s.DeletedAt.Valid {
+			continue
+		}
+		for _, v := range r.byName(s.Name) {
+			if v.Version == s.Version {
+				return errors.New(customerrors.ErrServiceVersionExists)
+			}
+		}
+	}
+	for i := range r.services {
+		if restore[r.services[i].ID] {
+			r.services[i].DeletedAt.Valid = false
+			r.services[i].DeletedAt.Time = time.Time{}
+		}
+	}
+	return nil
+}
+
+func (r *MemoryServiceRepository) ListDeletedBefore(before time.Time) ([]Service, error) {
+	log.Debug("fetching services deleted before", "before", before)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.deletedBefore(before), nil
+}
+
+func (r *MemoryServiceRepository) Purge(before time.Time, limit int) ([]Service, error) {
+	log.Debug("purging services deleted before", "before", before, "limit", limit)
+	defer r.write()()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	expired := r.deletedBefore(before)
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+	purge := map[uint]bool{}
+	for _, s := range expired {
+		purge[s.ID] = true
+	}
+	kept := r.services[:0]
+	names := map[string]bool{}
+	for _, s := range r.services {
+		if !purge[s.ID] {
+			kept = append(kept, s)
+			names[s.Name] = true
+		}
+	}
+	r.services = kept
+	for name := range r.currentVersions {
+		if !names[name] {
+			delete(r.currentVersions, name)
+		}
+	}
+	return expired, nil
+}
+
+func (r *MemoryServiceRepository) GetLiveAt(names []string, at time.Time) ([]Service, error) {
+	log.Debug("fetching services live at", "names", names, "at", at)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var output []Service
+	for _, s := range r.services {
+		if slices.Contains(names, s.Name) && liveAt(s, at) {
+			output = append(output, s)
+		}
+	}
+	return output, nil
+}
+
+func (r *MemoryServiceRepository) GetLiveNamesAt(at time.Time, desc bool, page ServicePage) ([]string, int64, error) {
+	log.Debug("fetching names of services live at", "at", at, "limit", page.Limit, "offset", page.Offset)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := []string{}
+	for _, s := range r.services {
+		if liveAt(s, at) && !slices.Contains(names, s.Name) {
+			names = append(names, s.Name)
+		}
+	}
+	sort.Strings(names)
+	if desc {
+		slices.Reverse(names)
+	}
+	total := int64(len(names))
+	names = names[min(page.Offset, len(names)):]
+	return names[:min(page.Limit, len(names))], total, nil
+}
+
+// liveAt reports whether the version was created at or before the time and deleted after it, if at all.
+func liveAt(s Service, at time.Time) bool {
+	return !s.CreatedAt.After(at) && (!s.DeletedAt.Valid || s.DeletedAt.Time.After(at))
+}
+
+func (r *MemoryServiceRepository) GetDeprecations(before time.Time) ([]Service, error) {
+	log.Debug("fetching deprecations", "before", before)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var output []Service
+	for _, s := range r.live() {
+		if s.Lifecycle == LifecycleDeprecated && s.SunsetDate != nil && !s.SunsetDate.After(before) {
+			output = append(output, s)
+		}
+	}
+	sort.SliceStable(output, func(i, j int) bool {
+		a, b := output[i], output[j]
+		if !a.SunsetDate.Equal(*b.SunsetDate) {
+			return a.SunsetDate.Before(*b.SunsetDate)
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.VersionKey < b.VersionKey
+	})
+	return output, nil
+}
+
+// LockDependencyEdges needs no lock of its own, transactions already hold txMu until they end.
+func (r *MemoryServiceRepository) LockDependencyEdges() ([]DependencyEdge, error) {
+	return r.GetDependencyEdges()
+}
+
+func (r *MemoryServiceRepository) GetDependencyEdges() ([]DependencyEdge, error) {
+	log.Debug("fetching dependency edges")
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var output []DependencyEdge
+	for _, s := range r.live() {
+		current, ok := r.currentVersions[s.Name]
+		for _, d := range s.Dependencies {
+			output = append(output, DependencyEdge{
+				Service:    s.Name,
+				Version:    s.Version,
+				Dependency: d.Name,
+				Constraint: d.Constraint,
+				Current:    ok && current.Version == s.Version,
+			})
+		}
+	}
+	sort.SliceStable(output, func(i, j int) bool {
+		a, b := output[i], output[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Version != b.Version {
+			av, _ := semver.Parse(a.Version)
+			bv, _ := semver.Parse(b.Version)
+			return av.Less(bv)
+		}
+		return a.Dependency < b.Dependency
+	})
+	return output, nil
+}
+
+func (r *MemoryServiceRepository) GetCurrentVersion(name string) (CurrentVersion, error) {
+	log.Debug("fetching current version", "name", name)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.currentVersions[name]
+	if !ok {
+		return c, errors.New(customerrors.ErrCurrentVersionNotFound)
+	}
+	return c, nil
+}
+
+func (r *MemoryServiceRepository) SetCurrentVersion(c *CurrentVersion) error {
+	log.Debug("setting current version", "name", c.Name, "version", c.Version)
+	defer r.write()()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.setCurrentVersion(c)
+	return nil
+}
+
+func (r *MemoryServiceRepository) AdvanceCurrentVersion(c *CurrentVersion) error {
+	log.Debug("advancing current version", "name", c.Name, "version", c.Version)
+	defer r.write()()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.currentVersions[c.Name]; ok && existing.VersionKey >= c.VersionKey {
+		return nil
+	}
+	r.setCurrentVersion(c)
+	return nil
+}
+
+// setCurrentVersion upserts the pointer, callers must hold the lock.
+func (r *MemoryServiceRepository) setCurrentVersion(c *CurrentVersion) {
+	now := time.Now()
+	c.CreatedAt = now
+	if existing, ok := r.currentVersions[c.Name]; ok {
+		c.CreatedAt = existing.CreatedAt
+	}
+	c.UpdatedAt = now
+	r.currentVersions[c.Name] = *c
+}
+
+func (r *MemoryServiceRepository) AddAuditEvent(e *AuditEvent) error {
+	log.Debug("adding audit event", "action", e.Action, "service", e.Service, "version", e.Version)
+	defer r.write()()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.ID = uint(len(r.auditEvents) + 1)
+	e.CreatedAt = time.Now()
+	r.auditEvents = append(r.auditEvents, *e)
+	return nil
+}
+
+func (r *MemoryServiceRepository) GetAuditEvents(search AuditSearch) ([]AuditEvent, error) {
+	log.Debug("fetching audit events", "service", search.Service, "actor", search.Actor)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	output := []AuditEvent{}
+	for _, e := range r.auditEvents {
+		if len(output) == search.Limit {
+			break
+		}
+		if search.Matches(e) {
+			output = append(output, e)
+		}
+	}
+	return output, nil
+}
+
+func (r *MemoryServiceRepository) GetFirstAuditEvents(search AuditSearch) ([]AuditEvent, error) {
+	log.Debug("fetching first audit events of the versions", "services", search.Services, "actions", search.Actions)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	output := []AuditEvent{}
+	seen := map[[2]string]bool{}
+	for _, e := range r.auditEvents {
+		if key := [2]string{e.Service, e.Version}; search.Matches(e) && !seen[key] {
+			seen[key] = true
+			output = append(output, e)
+		}
+	}
+	return output, nil
+}
+
+func (r *MemoryServiceRepository) GetLastAuditEvents(search AuditSearch) ([]AuditEvent, error) {
+	log.Debug("fetching last audit events of the services", "services", search.Services, "actions", search.Actions)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	last := map[string]int{}
+	for i, e := range r.auditEvents {
+		if search.Matches(e) {
+			last[e.Service] = i
+		}
+	}
+	output := []AuditEvent{}
+	for i, e := range r.auditEvents {
+		if last[e.Service] == i && search.Matches(e) {
+			output = append(output, e)
+		}
+	}
+	return output, nil
+}
+
+// Transaction runs fn with a repository whose writes are rolled back when it fails. A transaction run within
+// another one rolls back its own writes, like a savepoint.
+func (r *MemoryServiceRepository) Transaction(fn func(repo ServiceRepository) error) error {
+	defer r.write()()
+	r.mu.RLock()
+	// rows are copied, their labels and dependencies are replaced rather than changed in place.
+	lastID, services, currentVersions, auditEvents := r.lastID, slices.Clone(r.services), maps.Clone(r.currentVersions), slices.Clone(r.auditEvents)
+	r.mu.RUnlock()
+	err := fn(&MemoryServiceRepository{memoryRows: r.memoryRows, inTransaction: true})
+	if err != nil {
+		r.mu.Lock()
+		r.lastID, r.services, r.currentVersions, r.auditEvents = lastID, services, currentVersions, auditEvents
+		r.mu.Unlock()
+	}
+	return err
+}
+
+// write takes txMu for a write made outside of a transaction and returns the function releasing it, the writes of
+// a transaction are made under the lock the transaction holds.
+func (r *MemoryServiceRepository) write() func() {
+	if r.inTransaction {
+		return func() {}
+	}
+	r.txMu.Lock()
+	return r.txMu.Unlock
+}
+
+// add stores s unless a live row has the same name and version, callers must hold the lock.
+func (r *MemoryServiceRepository) add(s *Service) error {
+	for _, v := range r.byName(s.Name) {
+		if v.Version == s.Version {
+			return errors.New(customerrors.ErrServiceVersionExists)
+		}
+	}
+	now := time.Now()
+	r.lastID++
+	s.ID = r.lastID
+	s.Revision = 1
+	s.CreatedAt = now
+	s.UpdatedAt = now
+	row := *s
+	row.Bump = ""
+	row.Labels = labels(s.ID, s.Labels)
+	row.Dependencies = dependencies(s.ID, s.Dependencies)
+	r.services = append(r.services, row)
+	return nil
+}
+
+// live returns the rows which are not soft deleted, callers must hold the lock.
+func (r *MemoryServiceRepository) live() []Service {
+	var output []Service
+	for _, s := range r.services {
+		if !s.DeletedAt.Valid {
+			output = append(output, s)
+		}
+	}
+	return output
+}
+
+// byName returns the live versions of the service in version order, callers must hold the lock.
+func (r *MemoryServiceRepository) byName(name string) []Service {
+	var output []Service
+	for _, s := range r.live() {
+		if s.Name == name {
+			output = append(output, s)
+		}
+	}
+	sort.SliceStable(output, func(i, j int) bool { return output[i].SemVer().Less(output[j].SemVer()) })
+	return output
+}
+
+// deletedBefore returns the rows soft deleted before the given time, oldest deletion first, the caller must hold the lock.
+func (r *MemoryServiceRepository) deletedBefore(before time.Time) []Service {
+	var output []Service
+	for _, s := range r.services {
+		if s.DeletedAt.Valid && s.DeletedAt.Time.Before(before) {
+			output = append(output, s)
+		}
+	}
+	sort.SliceStable(output, func(i, j int) bool {
+		return output[i].DeletedAt.Time.Before(output[j].DeletedAt.Time)
+	})
+	return output
+}
+
+// labels returns a copy of the labels of the service row, so rows never share them with callers.
+func labels(serviceID uint, l Labels) Labels {
+	if l == nil {
+		return nil
+	}
+	output := make(Labels, len(l))
+	now := time.Now()
+	for i, label := range l {
+		label.ServiceID = serviceID
+		if label.CreatedAt.IsZero() {
+			label.CreatedAt = now
+		}
+		output[i] = label
+	}
+	return output
+}
+
+// dependencies returns a copy of the dependencies of the service row, so rows never share them with callers.
+func dependencies(serviceID uint, deps []Dependency) []Dependency {
+	if deps == nil {
+		return nil
+	}
+	output := make([]Dependency, len(deps))
+	now := time.Now()
+	for i, d := range deps {
+		d.ServiceID = serviceID
+		if d.CreatedAt.IsZero() {
+			d.CreatedAt = now
+		}
+		output[i] = d
+	}
+	return output
+}
+
+// latestVersion returns the highest version by semver precedence.
+func latestVersion(versions []Service) semver.Version {
+	latest := versions[0].SemVer()
+	for _, v := range versions[1:] {
+		if latest.Less(v.SemVer()) {
+			latest = v.SemVer()
+		}
+	}
+	return latest
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"sync"
+	"time"
+)
+
+// MemoryIdempotencyRepository is an in process implementation of IdempotencyRepository.
+type MemoryIdempotencyRepository struct {
+	mu     sync.Mutex
+	lastID uint
+	keys   map[string]IdempotencyKey
+}
+
+func NewMemoryIdempotencyRepository() *MemoryIdempotencyRepository {
+	return &MemoryIdempotencyRepository{keys: map[string]IdempotencyKey{}}
+}
+
+func (r *MemoryIdempotencyRepository) Reserve(k *IdempotencyKey) (IdempotencyKey, bool, error) {
+	log.Debug("reserving idempotency key", "key", k.Key)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for key, stored := range r.keys {
+		if !stored.ExpiresAt.After(now) {
+			delete(r.keys, key)
+		}
+	}
+	if stored, ok := r.keys[k.Key]; ok {
+		return stored, false, nil
+	}
+	r.lastID++
+	k.ID, k.CreatedAt = r.lastID, now
+	r.keys[k.Key] = *k
+	return IdempotencyKey{}, true, nil
+}
+
+func (r *MemoryIdempotencyRepository) Complete(k *IdempotencyKey) error {
+	log.Debug("completing idempotency key", "key", k.Key, "status", k.StatusCode)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.keys[k.Key]; ok && stored.ID == k.ID {
+		stored.ExpiresAt, stored.StatusCode, stored.Header, stored.Body = k.ExpiresAt, k.StatusCode, k.Header, k.Body
+		r.keys[k.Key] = stored
+	}
+	return nil
+}
+
+func (r *MemoryIdempotencyRepository) Release(k *IdempotencyKey) error {
+	log.Debug("releasing idempotency key", "key", k.Key)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.keys[k.Key]; ok && stored.ID == k.ID {
+		delete(r.keys, k.Key)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"errors"
+	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
+	log "github.com/suyog1pathak/services/pkg/logger"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryTeamRepository is an in process implementation of TeamRepository.
+type MemoryTeamRepository struct {
+	mu          sync.RWMutex
+	lastID      uint
+	lastOwnerID uint
+	teams       []Team
+}
+
+func NewMemoryTeamRepository() *MemoryTeamRepository {
+	return &MemoryTeamRepository{}
+}
+
+func (r *MemoryTeamRepository) Add(t *Team) error {
+	log.Debug("adding team", "team", t.Name)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index(t.Name) >= 0 {
+		return errors.New(customerrors.ErrTeamFoundWithSameName)
+	}
+	now := time.Now()
+	r.lastID++
+	t.ID, t.CreatedAt, t.UpdatedAt = r.lastID, now, now
+	for i := range t.Owners {
+		r.lastOwnerID++
+		t.Owners[i].ID, t.Owners[i].TeamID, t.Owners[i].CreatedAt, t.Owners[i].UpdatedAt = r.lastOwnerID, t.ID, now, now
+	}
+	r.teams = append(r.teams, clone(*t))
+	return nil
+}
+
+func (r *MemoryTeamRepository) List() ([]Team, error) {
+	log.Debug("fetching all teams")
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var output []Team
+	for _, t := range r.teams {
+		output = append(output, clone(t))
+	}
+	sort.Slice(output, func(i, j int) bool { return output[i].Name < output[j].Name })
+	return output, nil
+}
+
+func (r *MemoryTeamRepository) GetByName(name string) (Team, error) {
+	log.Debug("fetching team by name", "team", name)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.index(name)
+	if i < 0 {
+		log.Warn("team not found", "team", name)
+		return Team{}, errors.New(customerrors.ErrTeamNotFound)
+	}
+	return clone(r.teams[i]), nil
+}
+
+func (r *MemoryTeamRepository) Update(t *Team) error {
+	log.Debug("updating team", "team", t.Name)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(t.Name); i >= 0 {
+		r.teams[i].Description = t.Description
+		r.teams[i].UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (r *MemoryTeamRepository) Delete(name string) error {
+	log.Debug("deleting team", "team", name)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(name)
+	if i < 0 {
+		return errors.New(customerrors.ErrTeamNotFound)
+	}
+	r.teams = append(r.teams[:i], r.teams[i+1:]...)
+	return nil
+}
+
+func (r *MemoryTeamRepository) AddOwner(o *Owner) error {
+	log.Debug("adding owner", "team_id", o.TeamID, "email", o.Email)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.teams {
+		if r.teams[i].ID != o.TeamID {
+			continue
+		}
+		for _, existing := range r.teams[i].Owners {
+			// emails are compared the way the case-insensitive collation of the database does.
+			if strings.EqualFold(existing.Email, o.Email) {
+				return errors.New(customerrors.ErrOwnerExists)
+			}
+		}
+		now := time.Now()
+		r.lastOwnerID++
+		o.ID, o.CreatedAt, o.UpdatedAt = r.lastOwnerID, now, now
+		r.teams[i].Owners = append(r.teams[i].Owners, *o)
+		return nil
+	}
+	return errors.New(customerrors.ErrTeamNotFound)
+}
+
+func (r *MemoryTeamRepository) DeleteOwner(teamID uint, email string) error {
+	log.Debug("deleting owner", "team_id", teamID, "email", email)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.teams {
+		if r.teams[i].ID != teamID {
+			continue
+		}
+		for j, existing := range r.teams[i].Owners {
+			if strings.EqualFold(existing.Email, email) {
+				r.teams[i].Owners = append(r.teams[i].Owners[:j:j], r.teams[i].Owners[j+1:]...)
+				return nil
+			}
+		}
+	}
+	return errors.New(customerrors.ErrOwnerNotFound)
+}
+
+// Transaction runs fn with the repository itself and restores the teams it started with when fn fails. The memory
+// service repository runs one transaction at a time and teams are only changed in them, so nothing else is undone.
+func (r *MemoryTeamRepository) Transaction(_ ServiceRepository, fn func(teams TeamRepository) error) error {
+	r.mu.RLock()
+	lastID, lastOwnerID, teams := r.lastID, r.lastOwnerID, make([]Team, len(r.teams))
+	for i, t := range r.teams {
+		teams[i] = clone(t)
+	}
+	r.mu.RUnlock()
+	err := fn(r)
+	if err != nil {
+		r.mu.Lock()
+		r.lastID, r.lastOwnerID, r.teams = lastID, lastOwnerID, teams
+		r.mu.Unlock()
+	}
+	return err
+}
+
+// index returns the position of the team in r.teams or -1, the caller must hold the lock.
+func (r *MemoryTeamRepository) index(name string) int {
+	for i, t := range r.teams {
+		if t.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// clone copies the team so callers never share the owners slice with the repository.
+func clone(t Team) Team {
+	t.Owners = append([]Owner{}, t.Owners...)
+	return t
+}
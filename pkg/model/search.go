@@ -0,0 +1,188 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ways a search query is matched.
+const (
+	MatchContains = "contains"
+	MatchPrefix   = "prefix"
+)
+
+// fields a search query is matched against.
+const (
+	FieldName        = "name"
+	FieldDescription = "description"
+	FieldTags        = "tags"
+)
+
+var searchFields = []string{FieldName, FieldDescription, FieldTags}
+
+// ServiceSearch selects the services listed by GetServiceAndVersionCounts, a service is listed
+// when one of its live versions matches.
+type ServiceSearch struct {
+	// Query is matched case-insensitively, an empty query matches every service.
+	Query string
+	// Match is MatchContains to find the query anywhere in a field or MatchPrefix to find it at the start,
+	// for tags the query is matched against every tag.
+	Match string
+	// Fields are the fields the query is matched against, all of them when empty.
+	Fields []string
+	// Owners limits the search to the services owned by one of the teams.
+	Owners []string
+	// Labels limits the search to the service versions with matching labels.
+	Labels LabelSelector
+	// Lifecycles limits the search to the service versions in one of the lifecycle states.
+	Lifecycles []string
+}
+
+// NewServiceSearch parses the search query params, fields is a comma separated list of field names.
+func NewServiceSearch(query, match, fields string) (ServiceSearch, error) {
+	search := ServiceSearch{Query: strings.TrimSpace(query), Match: strings.ToLower(match)}
+	switch search.Match {
+	case "":
+		search.Match = MatchContains
+	case MatchContains, MatchPrefix:
+	default:
+		return search, fmt.Errorf("unknown match %q, expected contains or prefix", match)
+	}
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if !contains(searchFields, field) {
+			return search, fmt.Errorf("unknown search field %q, expected one of %s", field, strings.Join(searchFields, ", "))
+		}
+		search.Fields = append(search.Fields, field)
+	}
+	if len(search.Fields) == 0 {
+		search.Fields = searchFields
+	}
+	return search, nil
+}
+
+// Matches reports whether the service version matches the search.
+func (search ServiceSearch) Matches(s Service) bool {
+	if len(search.Owners) > 0 && !contains(search.Owners, string(s.Owner)) {
+		return false
+	}
+	if !search.Labels.Matches(s.Labels) {
+		return false
+	}
+	if len(search.Lifecycles) > 0 && !contains(search.Lifecycles, s.Lifecycle) {
+		return false
+	}
+	if search.Query == "" {
+		return true
+	}
+	query := strings.ToLower(search.Query)
+	match := func(value string) bool {
+		value = strings.ToLower(value)
+		if search.Match == MatchPrefix {
+			return strings.HasPrefix(value, query)
+		}
+		return strings.Contains(value, query)
+	}
+	for _, field := range search.fields() {
+		switch field {
+		case FieldName:
+			if match(s.Name) {
+				return true
+			}
+		case FieldDescription:
+			if match(s.Description) {
+				return true
+			}
+		case FieldTags:
+			if search.Match != MatchPrefix {
+				if match(s.Tags) {
+					return true
+				}
+				continue
+			}
+			for _, tag := range strings.Split(s.Tags, ",") {
+				if match(strings.TrimSpace(tag)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// condition returns the SQL condition and arguments matching the search, an empty condition matches everything.
+func (search ServiceSearch) condition() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if len(search.Owners) > 0 {
+		conditions = append(conditions, "owner IN ?")
+		args = append(args, search.Owners)
+	}
+	if len(search.Lifecycles) > 0 {
+		conditions = append(conditions, "lifecycle IN ?")
+		args = append(args, search.Lifecycles)
+	}
+	if query, queryArgs := search.queryCondition(); query != "" {
+		conditions = append(conditions, query)
+		args = append(args, queryArgs...)
+	}
+	if labels, labelArgs := search.Labels.condition(); labels != "" {
+		conditions = append(conditions, labels)
+		args = append(args, labelArgs...)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+func (search ServiceSearch) queryCondition() (string, []interface{}) {
+	if search.Query == "" {
+		return "", nil
+	}
+	query := escapeLike(strings.ToLower(search.Query))
+	pattern := "%" + query + "%"
+	if search.Match == MatchPrefix {
+		pattern = query + "%"
+	}
+	var conditions []string
+	var args []interface{}
+	for _, field := range search.fields() {
+		switch field {
+		case FieldName, FieldDescription:
+			conditions = append(conditions, fmt.Sprintf("LOWER(%s) LIKE ?", field))
+			args = append(args, pattern)
+		case FieldTags:
+			if search.Match != MatchPrefix {
+				conditions = append(conditions, "LOWER(tags) LIKE ?")
+				args = append(args, pattern)
+				continue
+			}
+			// a tag starts right after a comma once the spaces around the commas are removed.
+			conditions = append(conditions, "CONCAT(',', REPLACE(LOWER(tags), ' ', '')) LIKE ?")
+			args = append(args, "%,"+strings.ReplaceAll(query, " ", "")+"%")
+		}
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+func (search ServiceSearch) fields() []string {
+	if len(search.Fields) == 0 {
+		return searchFields
+	}
+	return search.Fields
+}
+
+// escapeLike escapes the LIKE wildcards so the value is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
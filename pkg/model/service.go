@@ -1,40 +1,92 @@
 package model
 
 import (
-	"errors"
-	"fmt"
-	"github.com/suyog1pathak/services/pkg/datastore"
-	customerrors "github.com/suyog1pathak/services/pkg/errors/service"
-	log "github.com/suyog1pathak/services/pkg/logger"
+	"github.com/suyog1pathak/services/pkg/semver"
 	"gorm.io/gorm"
-	"sync"
+	"time"
 )
 
-var db *gorm.DB
-var err error
-var once sync.Once
+// ServiceCount is a service in the listing with the aggregates of its versions it is sorted by.
+type ServiceCount struct {
+	Name       string
+	Count      int64
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	VersionKey string
+}
 
-func Setup() {
-	once.Do(func() {
-		db, err = datastore.GetDBConnection()
-		return
-	})
+// ServicePage selects a page of the service listing, Limit services from Offset, or when After or Before is set
+// the first Limit services sorted after or the last Limit services sorted before the position.
+type ServicePage struct {
+	Limit  int
+	Offset int
+	After  *ServiceCount
+	Before *ServiceCount
 }
 
-type ServiceCount struct {
-	Name  string
-	Count int64
+// ServiceSummary is the version of a service served to clients, the current version or the latest one
+// when it is not set, along with the number of live versions of the service.
+type ServiceSummary struct {
+	Service
+	TotalVersions int
 }
 
 type Service struct {
 	gorm.Model  `swaggerignore:"true"`
 	Name        string `json:"serviceName"`
 	Description string `json:"describe"`
-	Version     int    `json:"version" swaggerignore:"true"`
-	IsActive    bool   `json:"isActive" swaggertype:"boolean"`
-	Tags        string `json:"tags" `
+	Version     string `json:"version" example:"1.4.0"`
+	Major       int    `json:"-"`
+	Minor       int    `json:"-"`
+	Patch       int    `json:"-"`
+	Prerelease  string `json:"-"`
+	VersionKey  string `json:"-"`
+	// Revision counts the updates of the version, it starts at 1 and is served as its ETag.
+	Revision int `json:"revision" readonly:"true" example:"1"`
+	// IsActive is derived from the lifecycle for old clients, experimental and production versions are active.
+	IsActive bool `json:"isActive" swaggertype:"boolean" readonly:"true"`
+	// Lifecycle is the state of the version, it defaults to experimental for prereleases and production otherwise.
+	Lifecycle string `json:"lifecycle" enums:"experimental,production,deprecated,retired" example:"production"`
+	// DeprecatedAt is when the version was deprecated.
+	DeprecatedAt *time.Time `json:"deprecatedAt,omitempty" readonly:"true"`
+	// SunsetDate is when a deprecated version stops being served. It is cleared along with the successor and
+	// the deprecation time when the version goes back into production.
+	SunsetDate *time.Time `json:"sunsetDate,omitempty" example:"2027-01-31T00:00:00Z"`
+	// Successor is the service replacing a deprecated version.
+	Successor string `json:"successor,omitempty" example:"payments-v2"`
+	// Tags are the labels rendered as comma separated key=value pairs for old clients, they are derived from the labels
+	// and can not be written, a body with other tags than those of its labels is rejected.
+	Tags string `json:"tags" readonly:"true" example:"tier=1,pci"`
+	// Labels are the key=value pairs of the version, in the Kubernetes label syntax.
+	Labels Labels `json:"labels" swaggertype:"object,string" gorm:"foreignKey:ServiceID"`
+	// Owner is the name of the team owning the service, a new version keeps the owner of the service unless one is given.
+	Owner OwnerName `json:"owner" swaggertype:"string" example:"payments-platform"`
+	// Changelog is the release notes of the version, in Markdown.
+	Changelog string `json:"changelog,omitempty" example:"Adds refunds."`
+	// Author is who published the version, the X-User header of the request creating it.
+	Author string `json:"author,omitempty" readonly:"true" example:"jane.doe"`
+	// Dependencies are the services the version depends on, a new version keeps the dependencies of the service unless they are given.
+	Dependencies []Dependency `json:"dependencies,omitempty" gorm:"foreignKey:ServiceID"`
+	// Bump is the part of the latest version incremented when a new version is created without an explicit version.
+	Bump string `json:"bump,omitempty" gorm:"-" enums:"major,minor,patch"`
 } //@name ServiceModelDb
 
+// SetVersion sets the version along with the columns it is queried and ordered by.
+func (s *Service) SetVersion(v semver.Version) {
+	s.Version = v.String()
+	s.Major = v.Major
+	s.Minor = v.Minor
+	s.Patch = v.Patch
+	s.Prerelease = v.Prerelease
+	s.VersionKey = v.Key()
+}
+
+// SemVer returns the parsed version, versions are validated before they are stored.
+func (s *Service) SemVer() semver.Version {
+	v, _ := semver.Parse(s.Version)
+	return v
+}
+
 // only if you want to use table with custom name
 //type Tabler interface {
 //	TableName() string
@@ -46,130 +98,83 @@ type Service struct {
 
 //-----------------------------//
 
-func (s *Service) Add() error {
-	log.Debug("adding service", "service", s.Name)
-	result := db.Create(s)
-	if result.Error != nil {
-		log.Error("error in adding services", "service", s.Name, "error", result.Error.Error())
-		return result.Error
-	}
-	return nil
-}
-
-func (s *Service) List() ([]Service, error) {
-	log.Debug("fetching all services")
-	var output []Service
-	result := db.Where("is_active = ?", true).Find(&output)
-	if result.Error != nil {
-		log.Error("error in listing services", "error", result.Error.Error())
-		return output, result.Error
-	}
-	return output, nil
-}
-
-func (s *Service) GetByName() ([]Service, error) {
-	log.Debug("fetching service by name", "service", s.Name)
-	var output []Service
-	result := db.Where("name = ?", s.Name).Find(&output)
-	if result.Error != nil {
-		log.Error("error in getting service by name", "name", s.Name, "error", result.Error.Error())
-		return output, result.Error
-	}
-	if result.RowsAffected == 0 {
-		log.Warn("service not found", "name", s.Name)
-		return output, errors.New(customerrors.ErrServiceNotFound)
-	}
-	return output, nil
-}
-
-func (s *Service) GetByNameCount() (int64, error) {
-	log.Debug("fetching count service by name", "service", s.Name)
-	var output int64
-	result := db.Model(&Service{}).Debug().Where("name = ?", s.Name).Count(&output)
-	if result.Error != nil {
-		log.Error("error in fetching count service by name", "name", s.Name, "error", result.Error.Error())
-		return output, result.Error
-	}
-	if result.RowsAffected == 0 {
-		log.Warn("service not found", "name", s.Name)
-		return output, errors.New(customerrors.ErrServiceNotFound)
-	}
-	return output, nil
-}
-
-func (s *Service) GetServiceAndVersionCounts(query string, limit int, offset int, sort, dir string) ([]ServiceCount, int64, error) {
-	log.Debug("fetching service and version counts", "query", query, "limit", limit,
-		"offset", offset, "sort_by", sort, "direction", dir)
-	var count int64
-	/*
-		SELECT COUNT(count) as totalCount
-			FROM (
-				SELECT name, COUNT(*) as count
-				FROM `services` WHERE 'name' Like '%' AND `services`.`deleted_at` IS NULL
-				GROUP BY `name`
-			) as u
-	*/
-	totalCount := db.Table("(?) as u", db.Debug().Model(&Service{}).
-		Select("name, COUNT(*) as count").
-		Where("'name' Like ?", query).Group("name")).Select("COUNT(count) as totalCount").Scan(&count)
-
-	if totalCount.Error != nil {
-		log.Error("error in fetching service and version counts", "error", totalCount.Error.Error())
-		return []ServiceCount{}, 0, totalCount.Error
-	}
-
-	var results []ServiceCount
-	o := (offset - 1) * limit //from where we want to start
-	result := db.Limit(limit).Offset(o).Model(&Service{}).
-		Select("name, COUNT(*) as count"+fmt.Sprintf(", MAX(%s) as %s", sort, sort)).
-		Where("'name' Like ?", query).
-		Group("name").
-		Order(fmt.Sprintf("%s %s", sort, dir)).
-		Scan(&results)
-
-	if result.Error != nil {
-		log.Error("error in fetching service with pagination", "error", result.Error.Error())
-		return []ServiceCount{}, 0, result.Error
-	}
-
-	if result.RowsAffected == 0 {
-		return []ServiceCount{}, 0, errors.New(customerrors.ErrServiceNotFound)
-	}
-	return results, count, nil
-}
-
-func (s *Service) GetByNameAndVersion() (Service, error) {
-	log.Debug("fetching service with name and version", "name", s.Name, "version", s.Version)
-	var output Service
-	result := db.Where("name = ? and version = ?", s.Name, s.Version).Find(&output)
-	if result.Error != nil {
-		log.Error("error in fetching service with name and version", "name", s.Name, "version", s.Version, "error", result.Error)
-		return output, result.Error
-	}
-	if result.RowsAffected == 0 {
-		log.Warn("service not found", "name", s.Name, "version", s.Version)
-		return output, errors.New(customerrors.ErrServiceWithVersionNotFound)
-	}
-	return output, nil
-}
-
-func (s *Service) UpdateByNameAndVersion() error {
-	log.Debug("updating service with name and version", "name", s.Name, "version", s.Version)
-	result := db.Model(&s).Where("name = ? and version = ?", s.Name, s.Version).Updates(s)
-	if result.Error != nil {
-		log.Error("error in updating service with name and version", "name", s.Name, "version", s.Version, "error", result.Error.Error())
-		return result.Error
-	}
-	return nil
-}
-
-func (s *Service) DeleteByName() error {
-	log.Debug("deleting service", "name", s.Name)
-	//add Unscoped() for hard delete
-	result := db.Delete(s, "name = ?", s.Name)
-	if result.Error != nil {
-		log.Error("error in deleting service", "name", s.Name, "error", result.Error.Error())
-		return result.Error
-	}
-	return nil
+// ServiceRepository is the storage contract for services and their versions.
+// GormServiceRepository is backed by mysql, MemoryServiceRepository keeps everything in process
+// and is meant for unit tests and running the api locally without a database.
+type ServiceRepository interface {
+	// Add stores a new service version, ErrServiceVersionExists if the version is already there.
+	Add(s *Service) error
+	// AddNextVersion atomically stores s as the latest version of the service, soft deleted ones included, bumped
+	// by the given part. ErrServiceNotFound if the service has no live versions.
+	AddNextVersion(s *Service, bump string) error
+	// List returns all active service versions.
+	List() ([]Service, error)
+	// GetByName returns all versions of the service with their labels and dependencies, ErrServiceNotFound if there is none.
+	GetByName(name string) ([]Service, error)
+	// GetByNameCount returns the number of versions of the service.
+	GetByNameCount(name string) (int64, error)
+	// GetServiceAndVersionCounts returns a page of service names with their version counts along with the total number of services.
+	GetServiceAndVersionCounts(search ServiceSearch, sort ServiceSort, page ServicePage) ([]ServiceCount, int64, error)
+	// GetFacetCounts returns the number of services matching the search per label, active state and owner
+	// of their matching versions, along with the FacetTotal of services matching it.
+	GetFacetCounts(search ServiceSearch) ([]FacetCount, error)
+	// GetSummaries returns the summaries of the given services with their labels, services without live versions are left out.
+	GetSummaries(names []string) ([]ServiceSummary, error)
+	// GetByNameAndVersion returns a single service version with its labels and dependencies, ErrServiceWithVersionNotFound if there is none.
+	GetByNameAndVersion(name string, version string) (Service, error)
+	// UpdateByNameAndVersion updates the non-zero fields of the service version and the named fields even when
+	// they are zero, its labels along with the tags and its dependencies are replaced when they are set. The revision
+	// of the version is bumped and set on s, when s has a revision the version is only updated at that revision,
+	// ErrRevisionMismatch otherwise.
+	UpdateByNameAndVersion(s *Service, fields ...string) error
+	// DeleteByName soft deletes all versions of the service.
+	DeleteByName(name string) error
+	// DeleteByNameAndVersion soft deletes a single version of the service.
+	DeleteByNameAndVersion(name string, version string) error
+	// ListDeleted returns all soft deleted service versions.
+	ListDeleted() ([]Service, error)
+	// GetDeletedByName returns the soft deleted versions of the service, ErrDeletedServiceNotFound if there is none.
+	GetDeletedByName(name string) ([]Service, error)
+	// Restore clears the deletion of the given rows, ErrServiceVersionExists if one of them collides with a live version.
+	Restore(ids []uint) error
+	// ListDeletedBefore returns the service versions soft deleted before the given time.
+	ListDeletedBefore(before time.Time) ([]Service, error)
+	// Purge hard deletes up to limit service versions soft deleted before the given time, together with the
+	// current version pointers of services left without any row, and returns the deleted versions.
+	Purge(before time.Time, limit int) ([]Service, error)
+	// GetLiveAt returns the versions of the named services which were live at the given time with their labels and
+	// dependencies as they are now, including the versions deleted since. A version is told live by its creation and
+	// last deletion, a deletion undone by a restore since is not known.
+	GetLiveAt(names []string, at time.Time) ([]Service, error)
+	// GetLiveNamesAt returns a page of the names of the services with a version live at the given time, ordered by name,
+	// along with the number of them. The page is read by Limit and Offset.
+	GetLiveNamesAt(at time.Time, desc bool, page ServicePage) ([]string, int64, error)
+	// GetDeprecations returns the live deprecated versions with a sunset date before the given time, by sunset date.
+	GetDeprecations(before time.Time) ([]Service, error)
+	// GetDependencyEdges returns the dependencies declared by all live service versions.
+	GetDependencyEdges() ([]DependencyEdge, error)
+	// LockDependencyEdges is like GetDependencyEdges but reads the latest edges and keeps the dependencies from
+	// being written by anyone else until the transaction ends, so concurrent writers can not close a cycle.
+	LockDependencyEdges() ([]DependencyEdge, error)
+	// GetCurrentVersion returns the current version pointer of the service, ErrCurrentVersionNotFound if it is not set.
+	GetCurrentVersion(name string) (CurrentVersion, error)
+	// SetCurrentVersion points the service at the given version.
+	SetCurrentVersion(c *CurrentVersion) error
+	// AdvanceCurrentVersion is like SetCurrentVersion but only moves the pointer to a higher version,
+	// so concurrent publishers can not move it backwards.
+	AdvanceCurrentVersion(c *CurrentVersion) error
+	// AddAuditEvent appends the event to the audit log.
+	AddAuditEvent(e *AuditEvent) error
+	// GetAuditEvents returns up to search.Limit events matching the search, in the order they were appended.
+	GetAuditEvents(search AuditSearch) ([]AuditEvent, error)
+	// GetFirstAuditEvents returns the first event matching the search of every service version, ordered by id.
+	// The limit of the search is ignored.
+	GetFirstAuditEvents(search AuditSearch) ([]AuditEvent, error)
+	// GetLastAuditEvents returns the last event matching the search of every service, ordered by id.
+	// The limit of the search is ignored.
+	GetLastAuditEvents(search AuditSearch) ([]AuditEvent, error)
+	// Transaction runs fn against a repository whose writes are committed together when fn returns nil
+	// and rolled back when it returns an error. A transaction rolled back by a deadlock is run again,
+	// so fn must start over from what it reads through the repository.
+	Transaction(fn func(repo ServiceRepository) error) error
 }
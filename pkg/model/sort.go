@@ -0,0 +1,192 @@
+package model
+
+import (
+	"fmt"
+	"gorm.io/gorm/clause"
+	"sort"
+	"strings"
+)
+
+// public sort keys of the service listing.
+const (
+	SortName         = "name"
+	SortCreatedAt    = "created_at"
+	SortUpdatedAt    = "updated_at"
+	SortVersion      = "version"
+	SortVersionCount = "version_count"
+)
+
+// sort directions.
+const (
+	Asc  = "asc"
+	Desc = "desc"
+)
+
+// sortColumns maps the public sort keys to the columns of the services grouped by name,
+// nothing from the request ever reaches the query text.
+var sortColumns = map[string]string{
+	SortName:         "name",
+	SortCreatedAt:    "created_at",
+	SortUpdatedAt:    "updated_at",
+	SortVersion:      "version_key",
+	SortVersionCount: "count",
+}
+
+// SortKey is a single key of a ServiceSort.
+type SortKey struct {
+	Key  string
+	Desc bool
+}
+
+// ServiceSort orders the services listed by GetServiceAndVersionCounts, later keys break the ties of earlier ones
+// and services are finally ordered by name so pages are stable.
+type ServiceSort []SortKey
+
+// ParseServiceSort parses a comma separated list of sort keys, a key prefixed with - is sorted descending
+// and one prefixed with + ascending, keys without a prefix are sorted in dir.
+func ParseServiceSort(keys, dir string) (ServiceSort, error) {
+	var desc bool
+	switch strings.ToLower(dir) {
+	case Asc:
+	case "", Desc:
+		desc = true
+	default:
+		return nil, fmt.Errorf("unknown sort direction %q, expected asc or desc", dir)
+	}
+	var s ServiceSort
+	seen := map[string]bool{}
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		k := SortKey{Key: strings.ToLower(strings.TrimLeft(key, "+-")), Desc: desc}
+		switch key[0] {
+		case '-':
+			k.Desc = true
+		case '+':
+			k.Desc = false
+		}
+		if _, ok := sortColumns[k.Key]; !ok {
+			return nil, fmt.Errorf("unknown sort key %q, expected one of name, created_at, updated_at, version, version_count", key)
+		}
+		if seen[k.Key] {
+			return nil, fmt.Errorf("sort key %q is repeated", k.Key)
+		}
+		seen[k.Key] = true
+		s = append(s, k)
+	}
+	if len(s) == 0 {
+		s = ServiceSort{{Key: SortCreatedAt, Desc: desc}}
+	}
+	return s, nil
+}
+
+func (s ServiceSort) String() string {
+	keys := make([]string, len(s))
+	for i, k := range s {
+		keys[i] = k.Key
+		if k.Desc {
+			keys[i] = "-" + k.Key
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// keys returns the sort keys with the name tie breaker.
+func (s ServiceSort) keys() []SortKey {
+	for _, k := range s {
+		if k.Key == SortName {
+			return s
+		}
+	}
+	return append(append([]SortKey{}, s...), SortKey{Key: SortName})
+}
+
+// orderBy returns the ORDER BY clause of the services grouped by name, reversed to walk back from a position.
+func (s ServiceSort) orderBy(reverse bool) clause.OrderBy {
+	var order clause.OrderBy
+	for _, k := range s.keys() {
+		order.Columns = append(order.Columns, clause.OrderByColumn{
+			Column: clause.Column{Name: sortColumns[k.Key], Raw: true},
+			Desc:   k.Desc != reverse,
+		})
+	}
+	return order
+}
+
+// after returns the condition selecting the services sorted after the position, or before it when reverse is set:
+//
+//	k1 > v1 OR (k1 = v1 AND k2 > v2) OR ...
+func (s ServiceSort) after(position ServiceCount, reverse bool) (string, []interface{}) {
+	var conditions, equal []string
+	var args, equalArgs []interface{}
+	for _, k := range s.keys() {
+		column := sortColumns[k.Key]
+		op := ">"
+		if k.Desc != reverse {
+			op = "<"
+		}
+		condition := strings.Join(append(append([]string{}, equal...), fmt.Sprintf("%s %s ?", column, op)), " AND ")
+		conditions = append(conditions, "("+condition+")")
+		args = append(append(args, equalArgs...), position.value(k.Key))
+		equal = append(equal, column+" = ?")
+		equalArgs = append(equalArgs, position.value(k.Key))
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// sort orders the grouped services.
+func (s ServiceSort) sort(counts []ServiceCount) {
+	sort.SliceStable(counts, func(i, j int) bool { return s.compare(counts[i], counts[j]) < 0 })
+}
+
+// compare returns -1, 0 or 1 when a is sorted before, with or after b.
+func (s ServiceSort) compare(a, b ServiceCount) int {
+	for _, k := range s.keys() {
+		var c int
+		switch k.Key {
+		case SortName:
+			c = strings.Compare(a.Name, b.Name)
+		case SortCreatedAt:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		case SortUpdatedAt:
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		case SortVersion:
+			c = strings.Compare(a.VersionKey, b.VersionKey)
+		case SortVersionCount:
+			c = int(a.Count - b.Count)
+		}
+		if k.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return sign(c)
+		}
+	}
+	return 0
+}
+
+func (c ServiceCount) value(key string) interface{} {
+	switch key {
+	case SortCreatedAt:
+		return c.CreatedAt
+	case SortUpdatedAt:
+		return c.UpdatedAt
+	case SortVersion:
+		return c.VersionKey
+	case SortVersionCount:
+		return c.Count
+	}
+	return c.Name
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
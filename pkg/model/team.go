@@ -0,0 +1,72 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Team owns services, its owners are the people responsible for them.
+type Team struct {
+	ID          uint      `json:"-" gorm:"primaryKey"`
+	Name        string    `json:"name" example:"payments-platform"`
+	Description string    `json:"description"`
+	Owners      []Owner   `json:"owners" gorm:"foreignKey:TeamID"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+} //@name Team
+
+// Owner is a person responsible for the services of a team.
+type Owner struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	TeamID    uint      `json:"-"`
+	Name      string    `json:"name" example:"Jane Doe"`
+	Email     string    `json:"email" binding:"required,email" example:"jane@example.com"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+} //@name Owner
+
+// OwnerName is the name of the team owning a service, it is stored as NULL when the service has no owner
+// so the reference to the teams table holds.
+type OwnerName string
+
+func (o OwnerName) Value() (driver.Value, error) {
+	if o == "" {
+		return nil, nil
+	}
+	return string(o), nil
+}
+
+func (o *OwnerName) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*o = ""
+	case string:
+		*o = OwnerName(v)
+	case []byte:
+		*o = OwnerName(v)
+	default:
+		return fmt.Errorf("can not scan %T into OwnerName", value)
+	}
+	return nil
+}
+
+type TeamRepository interface {
+	// Add creates the team along with its owners, ErrTeamFoundWithSameName if the name is taken.
+	Add(t *Team) error
+	// List returns all teams with their owners ordered by name.
+	List() ([]Team, error)
+	// GetByName returns the team with its owners, ErrTeamNotFound if there is none.
+	GetByName(name string) (Team, error)
+	// Update updates the description of the team.
+	Update(t *Team) error
+	// Delete deletes the team and its owners, services it owned are left without an owner.
+	Delete(name string) error
+	// AddOwner adds an owner to a team, ErrOwnerExists if the team already has an owner with the same email.
+	AddOwner(o *Owner) error
+	// DeleteOwner removes an owner from a team, ErrOwnerNotFound if there is none.
+	DeleteOwner(teamID uint, email string) error
+	// Transaction runs fn with a repository writing in the transaction of repo, one handed out by
+	// ServiceRepository.Transaction, so changes to teams are committed or rolled back along with it.
+	Transaction(repo ServiceRepository, fn func(teams TeamRepository) error) error
+}
@@ -0,0 +1,247 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Constraint is a set of version ranges in the npm/composer style syntax:
+//
+//	^1.2        >=1.2.0 <2.0.0
+//	~1.2.3      >=1.2.3 <1.3.0
+//	1.x, 1.2.*  any version of the release line
+//	>=1.0 <2    comparators separated by spaces or commas must all match
+//	^1 || ^2    either range matches
+//
+// A prerelease version only matches when a comparator of the same range names a prerelease
+// of the same major.minor.patch, so ^1.2 never selects 2.0.0-rc.1 or 1.3.0-beta.
+type Constraint struct {
+	raw  string
+	sets [][]comparator
+}
+
+type comparator struct {
+	op string
+	v  Version
+}
+
+// ParseConstraint parses a version constraint.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: s}
+	if strings.TrimSpace(s) == "" {
+		return c, errors.New("empty constraint")
+	}
+	for _, set := range strings.Split(s, "||") {
+		tokens := tokenize(set)
+		if len(tokens) == 0 {
+			return c, fmt.Errorf("constraint %q: empty range", s)
+		}
+		var comparators []comparator
+		for _, token := range tokens {
+			expanded, err := parseComparator(token)
+			if err != nil {
+				return c, fmt.Errorf("constraint %q: %w", s, err)
+			}
+			comparators = append(comparators, expanded...)
+		}
+		c.sets = append(c.sets, comparators)
+	}
+	return c, nil
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// Check reports whether v satisfies the constraint.
+func (c Constraint) Check(v Version) bool {
+	for _, set := range c.sets {
+		if matchSet(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSet(set []comparator, v Version) bool {
+	for _, cmp := range set {
+		if !cmp.match(v) {
+			return false
+		}
+	}
+	if v.Prerelease == "" {
+		return true
+	}
+	for _, cmp := range set {
+		if cmp.v.Prerelease != "" && cmp.v.Major == v.Major && cmp.v.Minor == v.Minor && cmp.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparator) match(v Version) bool {
+	n := Compare(v, c.v)
+	switch c.op {
+	case "=":
+		return n == 0
+	case "!=":
+		return n != 0
+	case ">":
+		return n > 0
+	case ">=":
+		return n >= 0
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	}
+	return false
+}
+
+// tokenize splits a range into comparators, an operator separated from its version by a space is joined back.
+func tokenize(set string) []string {
+	fields := strings.FieldsFunc(set, func(r rune) bool { return r == ' ' || r == ',' || r == '\t' })
+	var tokens []string
+	for i := 0; i < len(fields); i++ {
+		token := fields[i]
+		if strings.TrimLeft(token, "=<>!^~") == "" && i+1 < len(fields) {
+			i++
+			token += fields[i]
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// parseComparator expands a single comparator, possibly with a partial version, into primitive comparators.
+func parseComparator(token string) ([]comparator, error) {
+	op := ""
+	for _, candidate := range []string{"~>", ">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(token, candidate) {
+			op = candidate
+			break
+		}
+	}
+	v, known, err := parsePartial(token[len(op):])
+	if err != nil {
+		return nil, err
+	}
+	between := func(lower, upper Version) []comparator {
+		return []comparator{{op: ">=", v: lower}, {op: "<", v: upper}}
+	}
+	// next is the first version after the release line named by the known components.
+	next := func() Version {
+		switch known {
+		case 1:
+			return Version{Major: v.Major + 1}
+		case 2:
+			return Version{Major: v.Major, Minor: v.Minor + 1}
+		}
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+	everything := []comparator{{op: ">=", v: Version{}}}
+	nothing := []comparator{{op: "<", v: Version{}}}
+
+	switch op {
+	case "", "=":
+		switch known {
+		case 0:
+			return everything, nil
+		case 3:
+			return []comparator{{op: "=", v: v}}, nil
+		}
+		return between(v, next()), nil
+	case "!=":
+		if known != 3 {
+			return nil, fmt.Errorf("%q: != needs a full version", token)
+		}
+		return []comparator{{op: "!=", v: v}}, nil
+	case ">":
+		switch known {
+		case 0:
+			return nothing, nil
+		case 3:
+			return []comparator{{op: ">", v: v}}, nil
+		}
+		return []comparator{{op: ">=", v: next()}}, nil
+	case ">=":
+		return []comparator{{op: ">=", v: v}}, nil
+	case "<":
+		if known == 0 {
+			return nothing, nil
+		}
+		return []comparator{{op: "<", v: v}}, nil
+	case "<=":
+		switch known {
+		case 0:
+			return everything, nil
+		case 3:
+			return []comparator{{op: "<=", v: v}}, nil
+		}
+		return []comparator{{op: "<", v: next()}}, nil
+	case "~", "~>":
+		switch known {
+		case 0:
+			return everything, nil
+		case 1:
+			return between(v, Version{Major: v.Major + 1}), nil
+		}
+		return between(v, Version{Major: v.Major, Minor: v.Minor + 1}), nil
+	case "^":
+		switch {
+		case known == 0:
+			return everything, nil
+		case v.Major > 0 || known == 1:
+			return between(v, Version{Major: v.Major + 1}), nil
+		case v.Minor > 0 || known == 2:
+			return between(v, Version{Minor: v.Minor + 1}), nil
+		}
+		return between(v, Version{Patch: v.Patch + 1}), nil
+	}
+	return nil, fmt.Errorf("%q: unknown operator", token)
+}
+
+// parsePartial parses a version which may miss components or use x, X or * as wildcards,
+// it returns how many leading components are known.
+func parsePartial(s string) (Version, int, error) {
+	str := strings.TrimPrefix(s, "v")
+	if str == "" {
+		return Version{}, 0, errors.New("missing version")
+	}
+	core, prerelease, hasPrerelease := strings.Cut(str, "-")
+	components := strings.Split(core, ".")
+	if len(components) > 3 {
+		return Version{}, 0, fmt.Errorf("%q: too many components", s)
+	}
+	known := 0
+	for _, component := range components {
+		if component == "x" || component == "X" || component == "*" {
+			break
+		}
+		known++
+	}
+	for _, component := range components[known:] {
+		if component != "x" && component != "X" && component != "*" {
+			return Version{}, 0, fmt.Errorf("%q: version component after a wildcard", s)
+		}
+	}
+	if hasPrerelease && known != 3 {
+		return Version{}, 0, fmt.Errorf("%q: a prerelease needs a full version", s)
+	}
+	if known == 0 {
+		return Version{}, 0, nil
+	}
+	v, err := Parse(strings.Join(components[:known], "."))
+	if err != nil {
+		return Version{}, 0, err
+	}
+	if hasPrerelease {
+		if err := validatePrerelease(prerelease); err != nil {
+			return Version{}, 0, fmt.Errorf("%q: %w", s, err)
+		}
+		v.Prerelease = prerelease
+	}
+	return v, known, nil
+}
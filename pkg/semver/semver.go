@@ -0,0 +1,232 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parts of a version which can be bumped.
+const (
+	Major = "major"
+	Minor = "minor"
+	Patch = "patch"
+)
+
+// bounds of the columns a version is stored in: the INT UNSIGNED major, minor and patch, the VARCHAR(64) version
+// and the VARBINARY(255) version_key.
+const (
+	MaxNumber    = math.MaxUint32
+	MaxLength    = 64
+	MaxKeyLength = 255
+)
+
+// Version is a semantic version (https://semver.org), build metadata is not supported.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// Parse parses a semantic version. A leading "v" is accepted and a missing minor or patch is read as 0,
+// so "v2" and "2.0" both parse to 2.0.0. Versions which can not be stored, with a part above MaxNumber or longer
+// than MaxLength, are rejected.
+func Parse(s string) (Version, error) {
+	var v Version
+	str := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if str == "" {
+		return v, errors.New("empty version")
+	}
+	if strings.Contains(str, "+") {
+		return v, fmt.Errorf("version %q: build metadata is not supported", s)
+	}
+	core, prerelease, hasPrerelease := strings.Cut(str, "-")
+	if hasPrerelease {
+		if err := validatePrerelease(prerelease); err != nil {
+			return v, fmt.Errorf("version %q: %w", s, err)
+		}
+		v.Prerelease = prerelease
+	}
+	numbers := strings.Split(core, ".")
+	if len(numbers) > 3 {
+		return v, fmt.Errorf("version %q: too many components", s)
+	}
+	fields := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, n := range numbers {
+		number, err := parseNumber(n)
+		if err != nil {
+			return v, fmt.Errorf("version %q: %w", s, err)
+		}
+		*fields[i] = number
+	}
+	if len(v.String()) > MaxLength {
+		return v, fmt.Errorf("version %q: longer than %d characters", s, MaxLength)
+	}
+	if len(v.Key()) > MaxKeyLength {
+		return v, fmt.Errorf("version %q: too many prerelease identifiers", s)
+	}
+	return v, nil
+}
+
+// MustParse is like Parse but panics if the version cannot be parsed.
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Bump returns the next version for the given part. A prerelease is released by the bump
+// which would produce it, e.g. a patch bump of 1.2.3-rc.1 gives 1.2.3. A part is not bumped past MaxNumber.
+func (v Version) Bump(part string) (Version, error) {
+	next, err := v.bump(part)
+	if err == nil && (next.Major > MaxNumber || next.Minor > MaxNumber || next.Patch > MaxNumber) {
+		return v, fmt.Errorf("version %s: the %s part can not be bumped past %d", v, part, MaxNumber)
+	}
+	return next, err
+}
+
+func (v Version) bump(part string) (Version, error) {
+	pre := v.Prerelease != ""
+	switch part {
+	case Major:
+		if pre && v.Minor == 0 && v.Patch == 0 {
+			return Version{Major: v.Major}, nil
+		}
+		return Version{Major: v.Major + 1}, nil
+	case Minor:
+		if pre && v.Patch == 0 {
+			return Version{Major: v.Major, Minor: v.Minor}, nil
+		}
+		return Version{Major: v.Major, Minor: v.Minor + 1}, nil
+	case Patch:
+		if pre {
+			return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}, nil
+		}
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}, nil
+	}
+	return v, fmt.Errorf("unknown version part %q, expected one of major, minor, patch", part)
+}
+
+// Compare returns -1, 0 or 1 when a has lower, equal or higher precedence than b.
+func Compare(a, b Version) int {
+	for _, d := range []int{a.Major - b.Major, a.Minor - b.Minor, a.Patch - b.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	switch {
+	case a.Prerelease == b.Prerelease:
+		return 0
+	case a.Prerelease == "":
+		return 1
+	case b.Prerelease == "":
+		return -1
+	}
+	as, bs := strings.Split(a.Prerelease, "."), strings.Split(b.Prerelease, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareIdentifier(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+// Less reports whether v has lower precedence than o.
+func (v Version) Less(o Version) bool {
+	return Compare(v, o) < 0
+}
+
+// Key returns a string whose byte order is the precedence order of versions,
+// so versions can be sorted and compared with MAX() in SQL.
+func (v Version) Key() string {
+	key := fmt.Sprintf("%010d.%010d.%010d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease == "" {
+		// '~' sorts after '-', a release has higher precedence than its prereleases.
+		return key + "~"
+	}
+	identifiers := strings.Split(v.Prerelease, ".")
+	for i, id := range identifiers {
+		if n, err := strconv.ParseUint(id, 10, 64); err == nil {
+			// numeric identifiers sort before alphanumeric ones and numerically among themselves.
+			identifiers[i] = fmt.Sprintf("0%020d", n)
+		} else {
+			identifiers[i] = "1" + id
+		}
+	}
+	// '!' sorts before every identifier character, a shorter set of identifiers has lower precedence.
+	return key + "-" + strings.Join(identifiers, "!")
+}
+
+func compareIdentifier(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		switch {
+		case an < bn:
+			return -1
+		case an > bn:
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func parseNumber(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New("empty version component")
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("version component %q has a leading zero", s)
+	}
+	n, err := strconv.ParseUint(s, 10, 32)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("version component %q is above %d", s, MaxNumber)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("version component %q is not a number", s)
+	}
+	return int(n), nil
+}
+
+func validatePrerelease(s string) error {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return errors.New("empty prerelease identifier")
+		}
+		for _, ch := range id {
+			if !(ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch == '-') {
+				return fmt.Errorf("prerelease identifier %q has invalid character %q", id, ch)
+			}
+		}
+	}
+	return nil
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,130 @@
+package semver
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for input, want := range map[string]string{
+		"1.4.0":        "1.4.0",
+		"v2":           "2.0.0",
+		"2.1":          "2.1.0",
+		"2.0.0-rc.1":   "2.0.0-rc.1",
+		"1.0.0-beta-2": "1.0.0-beta-2",
+	} {
+		v, err := Parse(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, want, v.String(), input)
+	}
+	for _, input := range []string{"", "1.2.3.4", "01.2.3", "1.a.3", "1.2.3+build", "1.2.3-", "1.2.3-rc..1"} {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestParseShouldRejectVersionsWhichCanNotBeStored(t *testing.T) {
+	v, err := Parse("4294967295.4294967295.4294967295")
+	assert.NoError(t, err)
+	assert.Len(t, v.Key(), 32+1)
+	_, err = Parse("1.0.0-" + strings.Repeat("a", MaxLength-6))
+	assert.NoError(t, err)
+
+	for _, input := range []string{
+		"4294967296.0.0",
+		"1.4294967296.0",
+		"1.0.99999999999999999999",
+		"1.0.0-" + strings.Repeat("a", MaxLength-5),
+		// short numeric identifiers are widened in the key.
+		"1.0.0-" + strings.Repeat("1.", 20) + "1",
+	} {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+
+	_, err = MustParse("4294967295.0.0").Bump(Major)
+	assert.Error(t, err)
+	next, err := MustParse("4294967295.0.0").Bump(Minor)
+	assert.NoError(t, err)
+	assert.Equal(t, "4294967295.1.0", next.String())
+}
+
+func TestCompareAndKeyShouldFollowPrecedence(t *testing.T) {
+	// ordered as in https://semver.org/#spec-item-11
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "1.10.0", "2.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, b := MustParse(ordered[i]), MustParse(ordered[i+1])
+		assert.Equal(t, -1, Compare(a, b), "%s < %s", a, b)
+		assert.Equal(t, 1, Compare(b, a), "%s > %s", b, a)
+		assert.Less(t, a.Key(), b.Key(), "key %s < %s", a, b)
+	}
+
+	keys := make([]string, len(ordered))
+	for i := range ordered {
+		keys[len(ordered)-1-i] = MustParse(ordered[i]).Key()
+	}
+	sort.Strings(keys)
+	for i, v := range ordered {
+		assert.Equal(t, MustParse(v).Key(), keys[i])
+	}
+}
+
+func TestBump(t *testing.T) {
+	for _, tc := range []struct{ from, part, want string }{
+		{"1.4.2", Major, "2.0.0"},
+		{"1.4.2", Minor, "1.5.0"},
+		{"1.4.2", Patch, "1.4.3"},
+		{"2.0.0-rc.1", Major, "2.0.0"},
+		{"1.5.0-rc.1", Minor, "1.5.0"},
+		{"1.5.1-rc.1", Minor, "1.6.0"},
+		{"1.5.1-rc.1", Patch, "1.5.1"},
+	} {
+		got, err := MustParse(tc.from).Bump(tc.part)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, got.String(), "%s bump %s", tc.from, tc.part)
+	}
+	_, err := MustParse("1.0.0").Bump("build")
+	assert.Error(t, err)
+}
+
+func TestConstraintCheck(t *testing.T) {
+	for _, tc := range []struct {
+		constraint string
+		match      []string
+		noMatch    []string
+	}{
+		{"^1.2", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0", "1.3.0-beta", "2.0.0-rc.1"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"1.x", []string{"1.0.0", "1.9.0"}, []string{"2.0.0", "0.9.0"}},
+		{"1.2.*", []string{"1.2.0", "1.2.7"}, []string{"1.3.0"}},
+		{"*", []string{"0.0.1", "9.9.9"}, []string{"1.0.0-rc.1"}},
+		{">=1.0 <2", []string{"1.0.0", "1.9.9"}, []string{"0.9.9", "2.0.0"}},
+		{">= 1.0, < 1.5", []string{"1.4.9"}, []string{"1.5.0"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"^1 || ^3", []string{"1.5.0", "3.0.0"}, []string{"2.0.0"}},
+		{">=2.0.0-rc.1", []string{"2.0.0-rc.2", "2.0.0", "2.1.0"}, []string{"2.0.0-beta", "2.1.0-rc.1"}},
+		{"!=1.2.3", []string{"1.2.4"}, []string{"1.2.3"}},
+	} {
+		c, err := ParseConstraint(tc.constraint)
+		assert.NoError(t, err, tc.constraint)
+		for _, v := range tc.match {
+			assert.True(t, c.Check(MustParse(v)), "%s should match %s", tc.constraint, v)
+		}
+		for _, v := range tc.noMatch {
+			assert.False(t, c.Check(MustParse(v)), "%s should not match %s", tc.constraint, v)
+		}
+	}
+	for _, input := range []string{"", "^", "1.x.3", "!=1.2", "^1.2-rc", ">=1.2.3.4", "^1 ||"} {
+		_, err := ParseConstraint(input)
+		assert.Error(t, err, input)
+	}
+}
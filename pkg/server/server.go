@@ -5,8 +5,13 @@ import (
 	"github.com/gin-gonic/gin"
 	sloggin "github.com/samber/slog-gin"
 	"github.com/suyog1pathak/services/docs"
+	"github.com/suyog1pathak/services/internal/purge"
+	"github.com/suyog1pathak/services/internal/service"
 	"github.com/suyog1pathak/services/pkg/config"
 	"github.com/suyog1pathak/services/pkg/controllers"
+	"github.com/suyog1pathak/services/pkg/cursor"
+	"github.com/suyog1pathak/services/pkg/datastore"
+	"github.com/suyog1pathak/services/pkg/jsonpatch"
 	"github.com/suyog1pathak/services/pkg/logger"
 	log "github.com/suyog1pathak/services/pkg/logger"
 	middlewarehealthcheck "github.com/suyog1pathak/services/pkg/middleware/healthcheck"
@@ -17,6 +22,7 @@ import (
 	"net/http"
 	"os/signal"
 	"strconv"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -29,6 +35,17 @@ import (
 //	@externalDocs.description	OpenAPI
 //	@externalDocs.url			https://swagger.io/resources/open-api/
 
+var (
+	repository      model.ServiceRepository
+	repositoryOnce  sync.Once
+	teams           model.TeamRepository
+	teamsOnce       sync.Once
+	cursorCodec     *cursor.Codec
+	cursorOnce      sync.Once
+	idempotency     model.IdempotencyRepository
+	idempotencyOnce sync.Once
+)
+
 func HandleRequest() {
 	c := config.GetConfig()
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -39,6 +56,8 @@ func HandleRequest() {
 		Handler: InitRouter(),
 	}
 
+	go newPurger().Start(ctx, c.App.PurgeInterval)
+
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Error("error ", "error", err.Error())
@@ -73,8 +92,13 @@ func HandleRequest() {
 
 func InitRouter() *gin.Engine {
 	docs.SwaggerInfo.Title = "services api"
-	model.Setup()
 	log := logger.Get()
+	svc := service.NewService(serviceRepository(), teamRepository(), cursors())
+	serviceController := controllers.NewServiceController(svc)
+	teamController := controllers.NewTeamController(svc)
+	graphController := controllers.NewGraphController(svc)
+	adminController := controllers.NewAdminController(newPurger())
+	idempotent := middlewareservice.Idempotency(idempotencyRepository(), config.GetConfig().App.IdempotencyTTL, config.GetConfig().App.IdempotencyLease)
 	router := gin.New()
 	router.Use(sloggin.New(log))
 	router.Use(gin.Recovery())
@@ -86,15 +110,100 @@ func InitRouter() *gin.Engine {
 		router.GET("/liveness", middlewarehealthcheck.HealthcheckCatchErrors(), controllers.Healthcheck)
 		router.GET("/readiness", middlewarehealthcheck.HealthcheckCatchErrors(), controllers.Healthcheck)
 
-		router.GET("/api/v1/services", middlewareservice.ServiceErrorHandler(), middlewareservice.ServiceQueryParams(), controllers.GetAllServices)
-		router.GET("/api/v1/services/:name", middlewareservice.ServiceErrorHandler(), controllers.GetServiceByName)
-		router.GET("/api/v1/services/:name/:version", middlewareservice.ServiceErrorHandler(), controllers.GetServiceNameAndVersion)
-		router.POST("/api/v1/services", middlewareservice.ServiceErrorHandler(), middlewareservice.ServiceBodyValidation(), controllers.CreateService)
-		router.PATCH("/api/v1/services/:name", middlewareservice.ServiceErrorHandler(), middlewareservice.ServiceBodyValidation(), controllers.UpdateService)
-		router.PATCH("/api/v1/services/:name/:version", middlewareservice.ServiceErrorHandler(), middlewareservice.ServiceBodyValidation(), controllers.UpdateServiceVersion)
-		router.DELETE("/api/v1/services/:name", middlewareservice.ServiceErrorHandler(), controllers.DeleteService)
-		//v1.DELETE("/services/:name/:version", controllers.DeleteServiceVersion)
+		router.GET("/api/v1/services", middlewareservice.ServiceErrorHandler(), middlewareservice.ServiceQueryParams(), serviceController.GetAllServices)
+		router.GET("/api/v1/services/:name", middlewareservice.ServiceErrorHandler(), serviceController.GetServiceByName)
+		router.GET("/api/v1/services/:name/versions", middlewareservice.ServiceErrorHandler(), serviceController.GetServiceVersions)
+		router.GET("/api/v1/services/:name/changelog", middlewareservice.ServiceErrorHandler(), serviceController.GetServiceChangelog)
+		router.GET("/api/v1/services/:name/diff", middlewareservice.ServiceErrorHandler(), serviceController.GetServiceDiff)
+		router.GET("/api/v1/services/:name/dependencies", middlewareservice.ServiceErrorHandler(), serviceController.GetServiceDependencies)
+		router.GET("/api/v1/services/:name/dependents", middlewareservice.ServiceErrorHandler(), serviceController.GetServiceDependents)
+		router.GET("/api/v1/services/:name/:version", middlewareservice.ServiceErrorHandler(), serviceController.GetServiceNameAndVersion)
+		router.POST("/api/v1/services", idempotent, middlewareservice.ServiceErrorHandler(), middlewareservice.ServiceBodyValidation(), serviceController.CreateService)
+		router.PATCH("/api/v1/services/:name", idempotent, middlewareservice.ServiceErrorHandler(), middlewareservice.ServiceBodyValidation(), serviceController.UpdateService)
+		router.PATCH("/api/v1/services/:name/:version", middlewareservice.ServiceErrorHandler(), middlewareservice.ServiceBodyValidation(jsonpatch.MergePatchMediaType, jsonpatch.MediaType), serviceController.UpdateServiceVersion)
+		router.POST("/api/v1/services/:name/promote/:version", middlewareservice.ServiceErrorHandler(), serviceController.PromoteServiceVersion)
+		router.POST("/api/v1/services/:name/rollback", middlewareservice.ServiceErrorHandler(), serviceController.RollbackService)
+		router.POST("/api/v1/services/:name/restore", middlewareservice.ServiceErrorHandler(), serviceController.RestoreService)
+		router.DELETE("/api/v1/services/:name", middlewareservice.ServiceErrorHandler(), serviceController.DeleteService)
+		router.DELETE("/api/v1/services/:name/:version", middlewareservice.ServiceErrorHandler(), serviceController.DeleteServiceVersion)
+
+		router.GET("/api/v1/audit", middlewareservice.ServiceErrorHandler(), serviceController.GetAudit)
+		router.GET("/api/v1/deprecations", middlewareservice.ServiceErrorHandler(), serviceController.GetDeprecations)
+		router.GET("/api/v1/facets", middlewareservice.ServiceErrorHandler(), middlewareservice.ServiceQueryParams(), serviceController.GetFacets)
+		router.GET("/api/v1/graph", middlewareservice.ServiceErrorHandler(), graphController.GetGraph)
+
+		router.GET("/api/v1/teams", middlewareservice.ServiceErrorHandler(), teamController.GetTeams)
+		router.POST("/api/v1/teams", middlewareservice.ServiceErrorHandler(), middlewareservice.TeamBodyValidation(), teamController.CreateTeam)
+		router.GET("/api/v1/teams/:team", middlewareservice.ServiceErrorHandler(), teamController.GetTeam)
+		router.PATCH("/api/v1/teams/:team", middlewareservice.ServiceErrorHandler(), middlewareservice.TeamBodyValidation(), teamController.UpdateTeam)
+		router.DELETE("/api/v1/teams/:team", middlewareservice.ServiceErrorHandler(), teamController.DeleteTeam)
+		router.POST("/api/v1/teams/:team/owners", middlewareservice.ServiceErrorHandler(), middlewareservice.OwnerBodyValidation(), teamController.AddOwner)
+		router.DELETE("/api/v1/teams/:team/owners/:email", middlewareservice.ServiceErrorHandler(), teamController.RemoveOwner)
+		router.GET("/api/v1/teams/:team/services", middlewareservice.ServiceErrorHandler(), middlewareservice.ServiceQueryParams(), teamController.GetTeamServices)
+
+		router.POST("/api/v1/admin/purge", middlewareservice.ServiceErrorHandler(), adminController.PurgeServices)
 	}
 
 	return router
 }
+
+// serviceRepository returns the service repository for the configured datastore, it is created once
+// so the router and the background purger share the same in memory datastore.
+func serviceRepository() model.ServiceRepository {
+	repositoryOnce.Do(func() {
+		if config.GetConfig().App.Datastore == "memory" {
+			logger.Warn("using in memory datastore, data will be lost on restart")
+			repository = model.NewMemoryServiceRepository()
+			return
+		}
+		db, _ := datastore.GetDBConnection()
+		repository = model.NewGormServiceRepository(db)
+	})
+	return repository
+}
+
+// teamRepository returns the team repository for the configured datastore, created once like the service repository.
+func teamRepository() model.TeamRepository {
+	teamsOnce.Do(func() {
+		if config.GetConfig().App.Datastore == "memory" {
+			teams = model.NewMemoryTeamRepository()
+			return
+		}
+		db, _ := datastore.GetDBConnection()
+		teams = model.NewGormTeamRepository(db)
+	})
+	return teams
+}
+
+// idempotencyRepository returns the idempotency key repository for the configured datastore, created once like the
+// service repository.
+func idempotencyRepository() model.IdempotencyRepository {
+	idempotencyOnce.Do(func() {
+		if config.GetConfig().App.Datastore == "memory" {
+			idempotency = model.NewMemoryIdempotencyRepository()
+			return
+		}
+		db, _ := datastore.GetDBConnection()
+		idempotency = model.NewGormIdempotencyRepository(db)
+	})
+	return idempotency
+}
+
+// cursors returns the codec of the pagination cursors, created once so the random secret used when none is
+// configured stays the same for every router.
+func cursors() *cursor.Codec {
+	cursorOnce.Do(func() {
+		secret := config.GetConfig().App.CursorSecret
+		if secret == "" {
+			logger.Warn("no cursor secret configured, pagination cursors will be invalid after a restart")
+		}
+		cursorCodec = cursor.NewCodec([]byte(secret))
+	})
+	return cursorCodec
+}
+
+// newPurger returns a purger for the configured retention window.
+func newPurger() *purge.Purger {
+	c := config.GetConfig()
+	return purge.NewPurger(serviceRepository(), c.App.PurgeAfter, c.App.PurgeBatchSize)
+}